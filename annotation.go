@@ -0,0 +1,133 @@
+package tracerr
+
+// AnnotationMergePolicy defines how MergedAnnotations combines
+// annotations with the same key from different errors in a chain.
+type AnnotationMergePolicy int
+
+const (
+	// OuterWins keeps only the value of the outermost error.
+	OuterWins AnnotationMergePolicy = iota
+	// CollectAll collects all values into []interface{}, outermost first.
+	CollectAll
+)
+
+// DefaultAnnotationMergePolicy is a policy used by MergedAnnotations.
+var DefaultAnnotationMergePolicy = OuterWins
+
+// AnnotationLimitPolicy defines what happens when a new annotation
+// is added to an error which already has MaxAnnotations annotations.
+type AnnotationLimitPolicy int
+
+const (
+	// DropNewAnnotations ignores new annotations, existing keys are still updated.
+	DropNewAnnotations AnnotationLimitPolicy = iota
+	// EvictOldestAnnotations removes the first added annotation.
+	EvictOldestAnnotations
+)
+
+// MaxAnnotations limits number of annotations of a single error,
+// so annotations added in a loop never grow unbounded.
+// Zero or negative value means no limit.
+var MaxAnnotations = 64
+
+// DefaultAnnotationLimitPolicy is a policy used when MaxAnnotations is reached.
+var DefaultAnnotationLimitPolicy = DropNewAnnotations
+
+// Annotate returns a copy of err with key-value pair attached.
+// The original error is not modified.
+//
+// Stack trace is added if err is not of type Error
+// and it will be nil if err is nil.
+func Annotate(err error, key string, value interface{}) Error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(*errorData)
+	if !ok {
+		e = trace(err, "", 2).(*errorData)
+	}
+	return e.annotate(key, value)
+}
+
+func (e *errorData) annotate(key string, value interface{}) *errorData {
+	c := e.clone()
+	_, exists := e.annotations[key]
+	if !exists && MaxAnnotations > 0 && len(e.annotations) >= MaxAnnotations &&
+		DefaultAnnotationLimitPolicy == DropNewAnnotations {
+		return c
+	}
+	c.annotations = make(map[string]interface{}, len(e.annotations)+1)
+	for k, v := range e.annotations {
+		c.annotations[k] = v
+	}
+	c.annotationKeys = make([]string, 0, len(e.annotationKeys)+1)
+	c.annotationKeys = append(c.annotationKeys, e.annotationKeys...)
+	if !exists {
+		for MaxAnnotations > 0 && len(c.annotationKeys) >= MaxAnnotations {
+			delete(c.annotations, c.annotationKeys[0])
+			c.annotationKeys = c.annotationKeys[1:]
+		}
+		c.annotationKeys = append(c.annotationKeys, key)
+	}
+	c.annotations[key] = value
+	return c
+}
+
+// Annotations returns key-value pairs attached to err.
+// It will be nil if err is not created by tracerr.
+// Use MergedAnnotations to get annotations of the whole chain.
+func Annotations(err error) map[string]interface{} {
+	e, ok := err.(*errorData)
+	if !ok || e.annotations == nil {
+		return nil
+	}
+	annotations := make(map[string]interface{}, len(e.annotations))
+	for k, v := range e.annotations {
+		annotations[k] = v
+	}
+	return annotations
+}
+
+// MergedAnnotations returns annotations of all errors in the chain of err,
+// combined according to DefaultAnnotationMergePolicy.
+func MergedAnnotations(err error) map[string]interface{} {
+	merged := map[string]interface{}{}
+	walk(err, func(current error) bool {
+		e, ok := current.(*errorData)
+		if !ok {
+			return true
+		}
+		for k, v := range e.annotations {
+			switch DefaultAnnotationMergePolicy {
+			case CollectAll:
+				values, _ := merged[k].([]interface{})
+				merged[k] = append(values, v)
+			default:
+				if _, ok := merged[k]; !ok {
+					merged[k] = v
+				}
+			}
+		}
+		return true
+	})
+	return merged
+}
+
+// PublicAnnotationKeys contains keys of annotations, which are safe
+// to display to users, such as request ID, see PublicAnnotations.
+var PublicAnnotationKeys []string
+
+// PublicAnnotations returns annotations of the chain of err the same way
+// as MergedAnnotations, but only with keys of PublicAnnotationKeys,
+// e.g. for HTTP responses, while the rest is for internal logs only.
+// It will be empty if there is no such annotation.
+func PublicAnnotations(err error) map[string]interface{} {
+	merged := MergedAnnotations(err)
+	public := make(map[string]interface{}, len(PublicAnnotationKeys))
+	for _, key := range PublicAnnotationKeys {
+		if value, ok := merged[key]; ok {
+			public[key] = value
+		}
+	}
+	return public
+}
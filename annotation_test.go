@@ -0,0 +1,174 @@
+package tracerr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestAnnotate(t *testing.T) {
+	if tracerr.Annotate(nil, "key", "value") != nil {
+		t.Errorf("tracerr.Annotate(nil, ...) = non-nil; want nil")
+	}
+
+	err := tracerr.New("some error")
+	annotated := tracerr.Annotate(err, "user", 42)
+	annotated = tracerr.Annotate(annotated, "request", "abc")
+	if tracerr.Annotations(err) != nil {
+		t.Errorf(
+			"tracerr.Annotations(err) = %#v; want nil",
+			tracerr.Annotations(err),
+		)
+	}
+	expected := map[string]interface{}{"user": 42, "request": "abc"}
+	if !reflect.DeepEqual(tracerr.Annotations(annotated), expected) {
+		t.Errorf(
+			"tracerr.Annotations(annotated) = %#v; want %#v",
+			tracerr.Annotations(annotated), expected,
+		)
+	}
+	if len(annotated.StackTrace()) != len(err.StackTrace()) {
+		t.Errorf(
+			"len(annotated.StackTrace()) = %#v; want %#v",
+			len(annotated.StackTrace()), len(err.StackTrace()),
+		)
+	}
+
+	regular := errors.New("regular error")
+	annotated = tracerr.Annotate(regular, "user", 42)
+	if annotated.Unwrap() != regular || len(annotated.StackTrace()) == 0 {
+		t.Errorf(
+			"tracerr.Annotate(regular, ...) = %#v; want traced regular error",
+			annotated,
+		)
+	}
+}
+
+func TestMergedAnnotations(t *testing.T) {
+	defer func() {
+		tracerr.DefaultAnnotationMergePolicy = tracerr.OuterWins
+	}()
+	inner := tracerr.Annotate(tracerr.New("inner"), "key", "inner")
+	inner = tracerr.Annotate(inner, "inner_only", 1)
+	outer := tracerr.Annotate(fmt.Errorf("outer: %w", inner), "key", "outer")
+	outer = tracerr.Annotate(outer, "outer_only", 2)
+
+	cases := []struct {
+		Policy   tracerr.AnnotationMergePolicy
+		Expected map[string]interface{}
+	}{
+		{
+			Policy: tracerr.OuterWins,
+			Expected: map[string]interface{}{
+				"key":        "outer",
+				"inner_only": 1,
+				"outer_only": 2,
+			},
+		},
+		{
+			Policy: tracerr.CollectAll,
+			Expected: map[string]interface{}{
+				"key":        []interface{}{"outer", "inner"},
+				"inner_only": []interface{}{1},
+				"outer_only": []interface{}{2},
+			},
+		},
+	}
+	for i, c := range cases {
+		tracerr.DefaultAnnotationMergePolicy = c.Policy
+		merged := tracerr.MergedAnnotations(outer)
+		if !reflect.DeepEqual(merged, c.Expected) {
+			t.Errorf(
+				"cases[%#v]: tracerr.MergedAnnotations(outer) = %#v; want %#v",
+				i, merged, c.Expected,
+			)
+		}
+	}
+}
+
+func TestAnnotationsJSONStable(t *testing.T) {
+	var err error = errors.New("some error")
+	for _, key := range []string{"user", "attempt", "region", "zone", "bucket", "id"} {
+		err = tracerr.Annotate(err, key, key+" value")
+	}
+	first, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		t.Fatalf("json.Marshal() error: %s", jsonErr)
+	}
+	for i := 0; i < 20; i++ {
+		b, jsonErr := json.Marshal(err)
+		if jsonErr != nil {
+			t.Fatalf("json.Marshal() error: %s", jsonErr)
+		}
+		if !bytes.Equal(b, first) {
+			t.Fatalf("json.Marshal() = %s; want %s", b, first)
+		}
+	}
+}
+
+func TestPublicAnnotations(t *testing.T) {
+	defer func() {
+		tracerr.PublicAnnotationKeys = nil
+	}()
+	err := tracerr.Annotate(errors.New("some error"), "request_id", "abc")
+	err = tracerr.Annotate(err, "sql", "SELECT * FROM users")
+	err = tracerr.Annotate(fmt.Errorf("get user: %w", err), "user", 42)
+
+	if public := tracerr.PublicAnnotations(err); len(public) != 0 {
+		t.Errorf("tracerr.PublicAnnotations() = %#v; want empty", public)
+	}
+
+	tracerr.PublicAnnotationKeys = []string{"request_id", "user", "missing"}
+	expected := map[string]interface{}{"request_id": "abc", "user": 42}
+	if public := tracerr.PublicAnnotations(err); !reflect.DeepEqual(public, expected) {
+		t.Errorf("tracerr.PublicAnnotations() = %#v; want %#v", public, expected)
+	}
+	inner := errors.Unwrap(tracerr.Unwrap(err))
+	if annotations := tracerr.Annotations(inner); annotations["sql"] != "SELECT * FROM users" {
+		t.Errorf("tracerr.Annotations() = %#v; want sql", annotations)
+	}
+	if merged := tracerr.MergedAnnotations(err); merged["sql"] != "SELECT * FROM users" {
+		t.Errorf("tracerr.MergedAnnotations() = %#v; want sql", merged)
+	}
+}
+
+func TestMaxAnnotations(t *testing.T) {
+	cases := []struct {
+		Policy       tracerr.AnnotationLimitPolicy
+		ExpectedKeys []string
+	}{
+		{
+			Policy:       tracerr.DropNewAnnotations,
+			ExpectedKeys: []string{"key0", "key63"},
+		},
+		{
+			Policy:       tracerr.EvictOldestAnnotations,
+			ExpectedKeys: []string{"key36", "key99"},
+		},
+	}
+
+	for i, c := range cases {
+		tracerr.DefaultAnnotationLimitPolicy = c.Policy
+		err := tracerr.New("some error")
+		for j := 0; j < 100; j++ {
+			err = tracerr.Annotate(err, fmt.Sprintf("key%d", j), j)
+		}
+		err = tracerr.Annotate(err, c.ExpectedKeys[0], "updated")
+		tracerr.DefaultAnnotationLimitPolicy = tracerr.DropNewAnnotations
+		annotations := tracerr.Annotations(err)
+		if len(annotations) != tracerr.MaxAnnotations {
+			t.Errorf("cases[%#v]: len(annotations) = %#v; want %#v", i, len(annotations), tracerr.MaxAnnotations)
+		}
+		if annotations[c.ExpectedKeys[0]] != "updated" {
+			t.Errorf("cases[%#v]: annotations[%#v] = %#v; want %#v", i, c.ExpectedKeys[0], annotations[c.ExpectedKeys[0]], "updated")
+		}
+		if _, ok := annotations[c.ExpectedKeys[1]]; !ok {
+			t.Errorf("cases[%#v]: annotations[%#v] is missing", i, c.ExpectedKeys[1])
+		}
+	}
+}
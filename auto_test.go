@@ -0,0 +1,40 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestSprintAuto(t *testing.T) {
+	defer func() {
+		tracerr.Verbose = false
+	}()
+	err := tracerr.Wrap(errors.New("some error"), "failed to read")
+	cases := []struct {
+		Err      error
+		Verbose  bool
+		Expected string
+	}{
+		{Err: nil, Expected: ""},
+		{Err: errors.New("some error"), Expected: "some error"},
+		{Err: err, Expected: "failed to read\nsome error"},
+		{Err: err, Verbose: true, Expected: tracerr.SprintSource(err)},
+		{Err: nil, Verbose: true, Expected: ""},
+	}
+
+	for i, c := range cases {
+		tracerr.Verbose = c.Verbose
+		if output := tracerr.SprintAuto(c.Err); output != c.Expected {
+			t.Errorf("cases[%#v]: tracerr.SprintAuto() = %#v; want %#v", i, output, c.Expected)
+		}
+	}
+
+	tracerr.Verbose = true
+	verbose := tracerr.SprintAuto(err)
+	tracerr.Verbose = false
+	if short := tracerr.SprintAuto(err); verbose == short {
+		t.Errorf("tracerr.SprintAuto() = %#v in both modes; want different output", short)
+	}
+}
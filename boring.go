@@ -0,0 +1,27 @@
+package tracerr
+
+import (
+	"errors"
+)
+
+// BoringErrors are expected errors, such as io.EOF or sql.ErrNoRows,
+// which are used for control flow, so capturing their stack trace is wasted.
+// Wrap and Ensure still wrap an error which is one of them (see errors.Is)
+// with a message, but with empty stack trace.
+// Like other package settings it should be changed on initialization.
+var BoringErrors []error
+
+// AddBoringError adds err to BoringErrors.
+func AddBoringError(err error) {
+	BoringErrors = append(BoringErrors, err)
+}
+
+// isBoring checks if err is one of BoringErrors.
+func isBoring(err error) bool {
+	for _, boring := range BoringErrors {
+		if errors.Is(err, boring) {
+			return true
+		}
+	}
+	return false
+}
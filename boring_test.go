@@ -0,0 +1,49 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestBoringErrors(t *testing.T) {
+	defer func() {
+		tracerr.BoringErrors = nil
+	}()
+	tracerr.AddBoringError(io.EOF)
+	tracerr.AddBoringError(context.Canceled)
+
+	cases := []struct {
+		Err            error
+		Message        string
+		ExpectedFrames bool
+	}{
+		{Err: io.EOF, Message: "failed to read"},
+		{Err: fmt.Errorf("request: %w", context.Canceled), Message: "failed to handle"},
+		{Err: io.ErrUnexpectedEOF, Message: "failed to read", ExpectedFrames: true},
+	}
+
+	for i, c := range cases {
+		err := tracerr.Wrap(c.Err, c.Message)
+		if frames := err.StackTrace(); (len(frames) > 0) != c.ExpectedFrames {
+			t.Errorf("cases[%#v]: len(err.StackTrace()) = %#v; want frames to be %t", i, len(frames), c.ExpectedFrames)
+		}
+		if messages := tracerr.Messages(err); !reflect.DeepEqual(messages, []string{c.Message}) {
+			t.Errorf("cases[%#v]: tracerr.Messages() = %#v; want %#v", i, messages, []string{c.Message})
+		}
+		if !errors.Is(err, c.Err) {
+			t.Errorf("cases[%#v]: errors.Is(err, c.Err) = false; want true", i)
+		}
+		if frames := tracerr.Ensure(c.Err).StackTrace(); (len(frames) > 0) != c.ExpectedFrames {
+			t.Errorf("cases[%#v]: len(tracerr.Ensure().StackTrace()) = %#v; want frames to be %t", i, len(frames), c.ExpectedFrames)
+		}
+		if frames := tracerr.WrapCtx(context.Background(), c.Err, c.Message).StackTrace(); (len(frames) > 0) != c.ExpectedFrames {
+			t.Errorf("cases[%#v]: len(tracerr.WrapCtx().StackTrace()) = %#v; want frames to be %t", i, len(frames), c.ExpectedFrames)
+		}
+	}
+}
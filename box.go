@@ -0,0 +1,127 @@
+package tracerr
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// BoxChars defines characters and title of a box drawn by SprintBoxed.
+type BoxChars struct {
+	Title       string
+	Horizontal  string
+	Vertical    string
+	TopLeft     string
+	TopRight    string
+	BottomLeft  string
+	BottomRight string
+	LeftTee     string
+	RightTee    string
+}
+
+// UnicodeBox draws a box with Unicode box-drawing characters.
+var UnicodeBox = BoxChars{
+	Title:       "✖ Error",
+	Horizontal:  "─",
+	Vertical:    "│",
+	TopLeft:     "┌",
+	TopRight:    "┐",
+	BottomLeft:  "└",
+	BottomRight: "┘",
+	LeftTee:     "├",
+	RightTee:    "┤",
+}
+
+// ASCIIBox draws a box with ASCII characters only for terminals,
+// which do not support Unicode.
+var ASCIIBox = BoxChars{
+	Title:       "x Error",
+	Horizontal:  "-",
+	Vertical:    "|",
+	TopLeft:     "+",
+	TopRight:    "+",
+	BottomLeft:  "+",
+	BottomRight: "+",
+	LeftTee:     "+",
+	RightTee:    "+",
+}
+
+// BoxStyle is a style of a box drawn by SprintBoxed.
+var BoxStyle = UnicodeBox
+
+// boxTab replaces tabs in boxed lines, so width of lines is known.
+const boxTab = "    "
+
+// SprintBoxed returns error message with indented stack trace
+// framed in a box with a title, drawn with BoxStyle characters.
+//
+// Source fragments are not displayed by default,
+// pass number of lines the same way as to SprintSource to display them.
+// MaxRenderBytes limits text inside the box, but not the box itself.
+func SprintBoxed(err error, nums ...int) string {
+	if err == nil {
+		return ""
+	}
+	format := FrameFormat
+	if format == nil {
+		format = Frame.String
+	}
+	withSource := false
+	var before, after int
+	if len(nums) > 0 {
+		before, after, withSource = calcRows(nums)
+	}
+	if e, ok := err.(*errorData); ok && e.noSource {
+		withSource = false
+	}
+	message := ErrorPrefix + errText(err)
+	rows := strings.Split(message, LineSeparator)
+	frames := StackTrace(err)
+	if len(frames) > 0 {
+		rows = append(rows, "")
+	}
+	for _, frame := range frames {
+		rows = append(rows, "  "+format(frame))
+		if withSource {
+			var source []string
+			for _, row := range sourceRows(nil, frame, before, after, false) {
+				if row != "" {
+					row = "  " + row
+				}
+				source = append(source, row)
+			}
+			rows = append(rows, source...)
+		}
+	}
+	if withSource && len(frames) > 0 {
+		// Drop an empty line after the last source fragment.
+		rows = rows[:len(rows)-1]
+	}
+
+	// Text inside the box is truncated, so the box is always drawn in full.
+	rows = strings.Split(truncate(strings.Join(rows, LineSeparator), len(message)), LineSeparator)
+	width := utf8.RuneCountInString(BoxStyle.Title)
+	for i, row := range rows {
+		rows[i] = strings.ReplaceAll(row, "\t", boxTab)
+		if n := utf8.RuneCountInString(rows[i]); n > width {
+			width = n
+		}
+	}
+	border := strings.Repeat(BoxStyle.Horizontal, width+2)
+	lines := make([]string, 0, len(rows)+4)
+	lines = append(lines,
+		BoxStyle.TopLeft+border+BoxStyle.TopRight,
+		boxLine(BoxStyle.Title, width),
+		BoxStyle.LeftTee+border+BoxStyle.RightTee,
+	)
+	for _, row := range rows {
+		lines = append(lines, boxLine(row, width))
+	}
+	lines = append(lines, BoxStyle.BottomLeft+border+BoxStyle.BottomRight)
+	return strings.Join(lines, LineSeparator)
+}
+
+// boxLine returns line padded to width between vertical lines of a box.
+func boxLine(line string, width int) string {
+	padding := strings.Repeat(" ", width-utf8.RuneCountInString(line))
+	return BoxStyle.Vertical + " " + line + padding + " " + BoxStyle.Vertical
+}
@@ -0,0 +1,109 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestSprintBoxed(t *testing.T) {
+	frames := []tracerr.Frame{
+		{Func: "main.read", Line: 42, Path: "/src/read.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	}
+	err := tracerr.Wrap(tracerr.CustomError(errors.New("some error"), frames), "failed to read")
+	cases := []struct {
+		Style    tracerr.BoxChars
+		Error    error
+		Expected string
+	}{
+		{
+			Style: tracerr.UnicodeBox,
+			Error: err,
+			Expected: "┌───────────────────────────────┐\n" +
+				"│ ✖ Error                       │\n" +
+				"├───────────────────────────────┤\n" +
+				"│ failed to read                │\n" +
+				"│ some error                    │\n" +
+				"│                               │\n" +
+				"│   /src/read.go:42 main.read() │\n" +
+				"│   /src/main.go:7 main.main()  │\n" +
+				"└───────────────────────────────┘",
+		},
+		{
+			Style: tracerr.ASCIIBox,
+			Error: err,
+			Expected: "+-------------------------------+\n" +
+				"| x Error                       |\n" +
+				"+-------------------------------+\n" +
+				"| failed to read                |\n" +
+				"| some error                    |\n" +
+				"|                               |\n" +
+				"|   /src/read.go:42 main.read() |\n" +
+				"|   /src/main.go:7 main.main()  |\n" +
+				"+-------------------------------+",
+		},
+		{
+			Style: tracerr.ASCIIBox,
+			Error: errors.New("regular error"),
+			Expected: "+---------------+\n" +
+				"| x Error       |\n" +
+				"+---------------+\n" +
+				"| regular error |\n" +
+				"+---------------+",
+		},
+		{
+			Style:    tracerr.UnicodeBox,
+			Error:    nil,
+			Expected: "",
+		},
+	}
+	defer func() {
+		tracerr.BoxStyle = tracerr.UnicodeBox
+	}()
+	for i, c := range cases {
+		tracerr.BoxStyle = c.Style
+		if output := tracerr.SprintBoxed(c.Error); output != c.Expected {
+			t.Errorf("cases[%#v]: tracerr.SprintBoxed() = %#v; want %#v", i, output, c.Expected)
+		}
+	}
+}
+
+func TestSprintBoxedSource(t *testing.T) {
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "main.read", Line: 42, Path: "/tmp/not_existing_file.go"},
+	})
+	expected := "┌────────────────────────────────────────────────────┐\n" +
+		"│ ✖ Error                                            │\n" +
+		"├────────────────────────────────────────────────────┤\n" +
+		"│ some error                                         │\n" +
+		"│                                                    │\n" +
+		"│   /tmp/not_existing_file.go:42 main.read()         │\n" +
+		"│   // source unavailable: /tmp/not_existing_file.go │\n" +
+		"└────────────────────────────────────────────────────┘"
+	if output := tracerr.SprintBoxed(err, 1); output != expected {
+		t.Errorf("tracerr.SprintBoxed() = %#v; want %#v", output, expected)
+	}
+}
+
+func TestSprintBoxedMaxRenderBytes(t *testing.T) {
+	defer func() {
+		tracerr.MaxRenderBytes = 0
+	}()
+	tracerr.MaxRenderBytes = 40
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "main.read", Line: 42, Path: "/src/read.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	})
+	expected := "┌─────────────────────────────────────────────────────┐\n" +
+		"│ ✖ Error                                             │\n" +
+		"├─────────────────────────────────────────────────────┤\n" +
+		"│ some error                                          │\n" +
+		"│                                                     │\n" +
+		"│   /src/read.go:42 main.read(...(truncated 30 bytes) │\n" +
+		"└─────────────────────────────────────────────────────┘"
+	if output := tracerr.SprintBoxed(err); output != expected {
+		t.Errorf("tracerr.SprintBoxed(err) = %#v; want %#v", output, expected)
+	}
+}
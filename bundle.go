@@ -0,0 +1,39 @@
+package tracerr
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// bundle contains source files set by SetSourceBundle.
+var bundle fs.FS
+
+// SetSourceBundle sets a file system to read source fragments from,
+// such as an archive of source files shipped with a binary,
+// which is useful when source code is not available on a server.
+// Files in fsys are keyed by path without leading slash,
+// e.g. "home/john/app/main.go" for "/home/john/app/main.go".
+//
+// Files missing in fsys are read from disk.
+// Source files which failed to read are not read again until next call.
+// Set it to nil to read from disk only.
+func SetSourceBundle(fsys fs.FS) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	bundle = fsys
+	cache = map[string][]string{}
+	failures = map[string]error{}
+}
+
+// readBundle reads a source file from fsys.
+func readBundle(fsys fs.FS, path string) ([]byte, error) {
+	if fsys == nil {
+		return nil, fs.ErrNotExist
+	}
+	name := strings.TrimPrefix(filepath.ToSlash(path), "/")
+	if !fs.ValidPath(name) {
+		return nil, fs.ErrInvalid
+	}
+	return fs.ReadFile(fsys, name)
+}
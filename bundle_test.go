@@ -0,0 +1,149 @@
+package tracerr_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/fs"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestSetSourceBundle(t *testing.T) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("srv/app/main.go")
+	if err != nil {
+		t.Fatalf("zip.Writer.Create() error: %s", err)
+	}
+	f.Write([]byte("package main\n\nfunc main() {\n\tpanic(\"bundled\")\n}\n"))
+	if err := w.Close(); err != nil {
+		t.Fatalf("zip.Writer.Close() error: %s", err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error: %s", err)
+	}
+
+	tracerr.SetSourceBundle(r)
+	defer tracerr.SetSourceBundle(nil)
+
+	err = tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "main.main", Line: 4, Path: "/srv/app/main.go"},
+		{Func: "tracerr_test.addFrameA", Line: 9, Path: "error_helper_test.go"},
+	})
+	rows := strings.Split(tracerr.SprintSource(err, 1, 0), "\n")
+	expected := []string{
+		"some error",
+		"",
+		"/srv/app/main.go:4 main.main()",
+		"3\tfunc main() {",
+		"4\t\tpanic(\"bundled\")",
+		"",
+	}
+	if len(rows) < len(expected)+3 {
+		t.Fatalf("rows = %#v; want at least %d rows", rows, len(expected)+3)
+	}
+	for i, row := range expected {
+		if rows[i] != row {
+			t.Errorf("rows[%d] = %#v; want %#v", i, rows[i], row)
+		}
+	}
+	// Falls back to disk.
+	if rows[7] != "8\tfunc addFrameA(message string) error {" {
+		t.Errorf("rows[7] = %#v; want source from disk", rows[7])
+	}
+}
+
+// slowFS is a file system which sleeps before reading a file.
+type slowFS struct {
+	fs.FS
+	delay time.Duration
+}
+
+func (f slowFS) Open(name string) (fs.File, error) {
+	time.Sleep(f.delay)
+	return f.FS.Open(name)
+}
+
+func TestSourceReadTimeout(t *testing.T) {
+	defer func(timeout time.Duration) {
+		tracerr.SourceReadTimeout = timeout
+	}(tracerr.SourceReadTimeout)
+	tracerr.SourceReadTimeout = 10 * time.Millisecond
+
+	tracerr.SetSourceBundle(slowFS{
+		FS:    fstest.MapFS{"srv/app/slow.go": {Data: []byte("package main\n")}},
+		delay: time.Second,
+	})
+	defer tracerr.SetSourceBundle(nil)
+
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "main.main", Line: 1, Path: "/srv/app/slow.go"},
+	})
+	start := time.Now()
+	output := tracerr.SprintSource(err)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("tracerr.SprintSource() took %s; want timeout", elapsed)
+	}
+	expected := "some error\n\n" +
+		"/srv/app/slow.go:1 main.main()\n" +
+		"// source unavailable: /srv/app/slow.go\n"
+	if output != expected {
+		t.Errorf("tracerr.SprintSource() = %#v; want %#v", output, expected)
+	}
+}
+
+// countFS is a file system which counts opened files.
+type countFS struct {
+	fs.FS
+	opens *atomic.Int32
+}
+
+func (f countFS) Open(name string) (fs.File, error) {
+	f.opens.Add(1)
+	return f.FS.Open(name)
+}
+
+func TestSourceReadFailureCached(t *testing.T) {
+	defer func(timeout time.Duration) {
+		tracerr.SourceReadTimeout = timeout
+	}(tracerr.SourceReadTimeout)
+	tracerr.SourceReadTimeout = 10 * time.Millisecond
+
+	opens := &atomic.Int32{}
+	tracerr.SetSourceBundle(countFS{FS: fstest.MapFS{}, opens: opens})
+	defer tracerr.SetSourceBundle(nil)
+
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "main.main", Line: 1, Path: "/srv/app/missing.go"},
+	})
+	expected := "some error\n\n" +
+		"/srv/app/missing.go:1 main.main()\n" +
+		"// source unavailable: /srv/app/missing.go\n"
+	for i := 0; i < 3; i++ {
+		if output := tracerr.SprintSource(err); output != expected {
+			t.Errorf("cases[%#v]: tracerr.SprintSource() = %#v; want %#v", i, output, expected)
+		}
+	}
+	if n := opens.Load(); n != 1 {
+		t.Errorf("opens = %#v; want %#v", n, 1)
+	}
+
+	tracerr.SetSourceBundle(countFS{
+		FS:    fstest.MapFS{"srv/app/missing.go": {Data: []byte("package main\n")}},
+		opens: opens,
+	})
+	expected = "some error\n\n" +
+		"/srv/app/missing.go:1 main.main()\n" +
+		"1\tpackage main\n" +
+		"2\t\n"
+	if output := tracerr.SprintSource(err); output != expected {
+		t.Errorf("tracerr.SprintSource() = %#v; want %#v", output, expected)
+	}
+}
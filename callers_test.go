@@ -0,0 +1,35 @@
+package tracerr_test
+
+import (
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestCallers(t *testing.T) {
+	frames := tracerr.Callers(0)
+	if len(frames) < 2 {
+		t.Fatalf("frames = %#v; want at least 2 frames", frames)
+	}
+	if frames[0].Func != "github.com/ztrue/tracerr_test.TestCallers" {
+		t.Errorf("frames[0] = %#v; want TestCallers", frames[0])
+	}
+	if frames := tracerr.Callers(-1); len(frames) == 0 || frames[0].Func != "github.com/ztrue/tracerr_test.TestCallers" {
+		t.Errorf("tracerr.Callers(-1) = %#v; want TestCallers on top", frames)
+	}
+
+	skipped := callersOf(1)
+	if len(skipped) == 0 || skipped[0].Func != "github.com/ztrue/tracerr_test.TestCallers" {
+		t.Errorf("callersOf(1) = %#v; want TestCallers on top", skipped)
+	}
+	if own := callersOf(0); len(own) == 0 || own[0].Func != "github.com/ztrue/tracerr_test.callersOf" {
+		t.Errorf("callersOf(0) = %#v; want callersOf on top", own)
+	}
+	if frames := tracerr.Callers(1000); len(frames) != 0 {
+		t.Errorf("tracerr.Callers(1000) = %#v; want empty", frames)
+	}
+}
+
+func callersOf(skip int) []tracerr.Frame {
+	return tracerr.Callers(skip)
+}
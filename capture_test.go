@@ -0,0 +1,35 @@
+package tracerr_test
+
+import (
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestCaptureDeepStack(t *testing.T) {
+	defaultCap := tracerr.DefaultCap
+	defer func() {
+		tracerr.DefaultCap = defaultCap
+	}()
+	depth := 100
+	for _, c := range []int{1, 20, 64, 128} {
+		tracerr.DefaultCap = c
+		expected := len(tracerr.StackTrace(addFrames(1, "some error"))) + depth - 1
+		frames := tracerr.StackTrace(addFrames(depth, "some error"))
+		if len(frames) != expected {
+			t.Errorf("DefaultCap = %d: len(frames) = %d; want %d", c, len(frames), expected)
+		}
+		count := 0
+		for _, frame := range frames {
+			if frame.Func == "github.com/ztrue/tracerr_test.addFrames" {
+				count++
+			}
+		}
+		if count != depth {
+			t.Errorf("DefaultCap = %d: %d frames of addFrames; want %d", c, count, depth)
+		}
+		if last := frames[len(frames)-1]; last.Func != "runtime.goexit" {
+			t.Errorf("DefaultCap = %d: last frame = %#v; want runtime.goexit", c, last)
+		}
+	}
+}
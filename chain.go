@@ -0,0 +1,132 @@
+package tracerr
+
+import (
+	"errors"
+	"reflect"
+)
+
+// maxChainDepth limits number of errors visited in a chain,
+// so malformed errors which wrap each other never cause an infinite loop.
+const maxChainDepth = 1000
+
+// chainKey identifies an error with reference semantics in a chain.
+type chainKey struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// keyOf returns a key of err if it is a pointer or other reference type.
+// Values of other types can not be compared safely.
+func keyOf(err error) (chainKey, bool) {
+	v := reflect.ValueOf(err)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return chainKey{typ: v.Type(), ptr: v.Pointer()}, true
+	}
+	return chainKey{}, false
+}
+
+// walk calls fn for every error in the chain of err, outermost first,
+// until fn returns false.
+// It returns true if walking is stopped because of a cycle.
+func walk(err error, fn func(err error) bool) bool {
+	var visited []chainKey
+	for depth := 0; err != nil; depth++ {
+		if depth >= maxChainDepth {
+			return true
+		}
+		if key, ok := keyOf(err); ok {
+			for _, k := range visited {
+				if k == key {
+					return true
+				}
+			}
+			visited = append(visited, key)
+		}
+		if !fn(err) {
+			return false
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// HasCycle checks if the chain of err is malformed,
+// so errors wrap each other, including errors joined by errors.Join.
+//
+// All functions of the package, which walk the chain of errors,
+// stop at a cycle rather than loop infinitely.
+func HasCycle(err error) bool {
+	return hasCycle(err, nil)
+}
+
+func hasCycle(err error, path []chainKey) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if len(path) >= maxChainDepth {
+			return true
+		}
+		if key, ok := keyOf(err); ok {
+			for _, k := range path {
+				if k == key {
+					return true
+				}
+			}
+			path = append(path, key)
+		}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, child := range joined.Unwrap() {
+				if hasCycle(child, path[:len(path):len(path)]) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
+
+// IsSkipping reports whether any error in the chain of err matches target
+// the same way as errors.Is, but layers for which skip returns true
+// are neither compared with target nor asked by their Is method,
+// while errors wrapped by them are still checked.
+// It helps to debug chains with a layer, which Is method matches too broadly.
+func IsSkipping(err, target error, skip func(error) bool) bool {
+	if err == nil || target == nil {
+		return err == target
+	}
+	budget := maxChainDepth
+	return isSkipping(err, target, skip, &budget)
+}
+
+// isSkipping checks chain of err for IsSkipping,
+// where budget is a number of errors left to visit in all joined chains.
+func isSkipping(err, target error, skip func(error) bool, budget *int) bool {
+	comparable := reflect.TypeOf(target).Comparable()
+	found := false
+	walk(err, func(current error) bool {
+		*budget--
+		if *budget < 0 {
+			return false
+		}
+		if skip == nil || !skip(current) {
+			if comparable && current == target {
+				found = true
+				return false
+			}
+			if x, ok := current.(interface{ Is(error) bool }); ok && x.Is(target) {
+				found = true
+				return false
+			}
+		}
+		if joined, ok := current.(interface{ Unwrap() []error }); ok {
+			for _, child := range joined.Unwrap() {
+				if child != nil && isSkipping(child, target, skip, budget) {
+					found = true
+					return false
+				}
+			}
+		}
+		return true
+	})
+	return found
+}
@@ -0,0 +1,149 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ztrue/tracerr"
+)
+
+type cyclicError struct {
+	next error
+}
+
+func (e *cyclicError) Error() string {
+	return "cyclic error"
+}
+
+func (e *cyclicError) Unwrap() error {
+	return e.next
+}
+
+type cyclicJoinError struct {
+	errs []error
+}
+
+func (e *cyclicJoinError) Error() string {
+	return "cyclic join error"
+}
+
+func (e *cyclicJoinError) Unwrap() []error {
+	return e.errs
+}
+
+func TestHasCycle(t *testing.T) {
+	a := &cyclicError{}
+	b := &cyclicError{next: a}
+	a.next = b
+	joined := &cyclicJoinError{}
+	joined.errs = []error{errors.New("valid"), fmt.Errorf("wrapped: %w", joined)}
+	self := &cyclicJoinError{}
+	self.errs = []error{self, self}
+
+	cases := []struct {
+		Error    error
+		Expected bool
+	}{
+		{Error: nil, Expected: false},
+		{Error: errors.New("regular error"), Expected: false},
+		{Error: tracerr.Wrap(fmt.Errorf("wrapped: %w", errors.New("root")), ""), Expected: false},
+		{Error: errors.Join(errors.New("a"), errors.New("b")), Expected: false},
+		{Error: a, Expected: true},
+		{Error: tracerr.Wrap(b, ""), Expected: true},
+		{Error: joined, Expected: true},
+		{Error: self, Expected: true},
+	}
+	for i, c := range cases {
+		if tracerr.HasCycle(c.Error) != c.Expected {
+			t.Errorf(
+				"tracerr.HasCycle(cases[%#v].Error) = %#v; want %#v",
+				i, tracerr.HasCycle(c.Error), c.Expected,
+			)
+		}
+	}
+}
+
+func TestCycleTermination(t *testing.T) {
+	a := &cyclicError{}
+	b := &cyclicError{next: a}
+	a.next = b
+	err := tracerr.Wrap(b, "")
+	joined := &cyclicJoinError{}
+	joined.errs = []error{errors.New("valid"), joined}
+	self := &cyclicJoinError{}
+	self.errs = []error{self, self}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tracerr.Cause(err)
+		tracerr.RootMessage(err)
+		tracerr.Errors(err)
+		if errs := tracerr.Errors(joined); len(errs) != 1 || errs[0].Error() != "valid" {
+			t.Errorf("tracerr.Errors(joined) = %#v; want valid error", errs)
+		}
+		if errs := tracerr.Errors(self); len(errs) != 0 {
+			t.Errorf("tracerr.Errors(self) = %#v; want empty", errs)
+		}
+		tracerr.MergedAnnotations(err)
+		tracerr.StatusOf(err)
+		tracerr.FromPkgErrors(b)
+		_ = err.Error()
+		_ = tracerr.Wrap(fmt.Errorf("wrapped: %w", err), "").Error()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("chain walking has not terminated")
+	}
+}
+
+type broadIsError struct {
+	err error
+}
+
+func (e *broadIsError) Error() string {
+	return "broad: " + e.err.Error()
+}
+
+func (e *broadIsError) Unwrap() error {
+	return e.err
+}
+
+func (e *broadIsError) Is(target error) bool {
+	return true
+}
+
+func TestIsSkipping(t *testing.T) {
+	errNotFound := errors.New("not found")
+	errTimeout := errors.New("timeout")
+	err := tracerr.Wrap(&broadIsError{err: tracerr.Wrap(errNotFound, "")}, "")
+	skipBroad := func(err error) bool {
+		_, ok := err.(*broadIsError)
+		return ok
+	}
+	joined := &cyclicJoinError{}
+	joined.errs = []error{joined, joined}
+	cases := []struct {
+		Err      error
+		Target   error
+		Skip     func(error) bool
+		Expected bool
+	}{
+		{Err: err, Target: errTimeout, Expected: true},
+		{Err: err, Target: errTimeout, Skip: skipBroad, Expected: false},
+		{Err: err, Target: errNotFound, Skip: skipBroad, Expected: true},
+		{Err: errors.Join(errTimeout, err), Target: errNotFound, Skip: skipBroad, Expected: true},
+		{Err: joined, Target: errTimeout, Expected: false},
+		{Err: nil, Target: errTimeout, Expected: false},
+		{Err: nil, Target: nil, Expected: true},
+	}
+
+	for i, c := range cases {
+		if is := tracerr.IsSkipping(c.Err, c.Target, c.Skip); is != c.Expected {
+			t.Errorf("cases[%#v]: tracerr.IsSkipping() = %#v; want %#v", i, is, c.Expected)
+		}
+	}
+}
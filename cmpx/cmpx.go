@@ -0,0 +1,34 @@
+// Package cmpx teaches github.com/google/go-cmp/cmp to compare errors of tracerr.
+//
+// It is a separate package, so programs which import tracerr do not import go-cmp.
+package cmpx
+
+import (
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ztrue/tracerr"
+)
+
+// EquateErrors returns a cmp.Option, which compares errors by tracerr.Equal
+// if at least one of them is of type tracerr.Error,
+// so errors created at different places but with the same messages
+// and the same underlying error are equal.
+func EquateErrors() cmp.Option {
+	return cmp.FilterValues(isTraced, cmp.Comparer(equal))
+}
+
+// isTraced checks if x and y are errors and at least one of them is traced.
+func isTraced(x, y interface{}) bool {
+	errX, okX := x.(error)
+	errY, okY := y.(error)
+	if !okX || !okY {
+		return false
+	}
+	_, tracedX := errX.(tracerr.Error)
+	_, tracedY := errY.(tracerr.Error)
+	return tracedX || tracedY
+}
+
+func equal(x, y interface{}) bool {
+	return tracerr.Equal(x.(error), y.(error))
+}
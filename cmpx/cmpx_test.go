@@ -0,0 +1,37 @@
+package cmpx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ztrue/tracerr"
+	"github.com/ztrue/tracerr/cmpx"
+)
+
+var errNotFound = errors.New("not found")
+
+type result struct {
+	ID  int
+	Err error
+}
+
+func read(id int) result {
+	return result{ID: id, Err: tracerr.Wrap(errNotFound, "failed to read")}
+}
+
+func TestEquateErrors(t *testing.T) {
+	expected := result{ID: 1, Err: tracerr.Wrap(errNotFound, "failed to read")}
+	if diff := cmp.Diff(expected, read(1), cmpx.EquateErrors()); diff != "" {
+		t.Errorf("read() mismatch (-want +got):\n%s", diff)
+	}
+	other := result{ID: 1, Err: tracerr.Wrap(errNotFound, "failed to write")}
+	if diff := cmp.Diff(other, read(1), cmpx.EquateErrors()); diff == "" {
+		t.Errorf("cmp.Diff() = empty; want diff of messages")
+	}
+	plain := result{ID: 1, Err: errNotFound}
+	if diff := cmp.Diff(plain, result{ID: 1, Err: tracerr.Wrap(errNotFound, "")}, cmpx.EquateErrors()); diff != "" {
+		t.Errorf("cmp.Diff() mismatch (-want +got):\n%s", diff)
+	}
+}
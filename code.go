@@ -0,0 +1,37 @@
+package tracerr
+
+// WithCode returns a copy of err with a machine-readable code attached,
+// such as "USER_NOT_FOUND", which is a part of a domain error taxonomy
+// unlike HTTP status code.
+// The original error is not modified.
+//
+// Stack trace is added if err is not of type Error
+// and it will be nil if err is nil.
+func WithCode(err error, code string) Error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(*errorData)
+	if !ok {
+		e = trace(err, "", 2).(*errorData)
+	}
+	c := e.clone()
+	c.code = code
+	return c
+}
+
+// CodeOf returns code attached to the outermost error
+// in the chain of err by WithCode.
+// It will be empty and false if there is no code.
+func CodeOf(err error) (string, bool) {
+	code, found := "", false
+	walk(err, func(current error) bool {
+		e, ok := current.(*errorData)
+		if ok && e.code != "" {
+			code, found = e.code, true
+			return false
+		}
+		return true
+	})
+	return code, found
+}
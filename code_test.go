@@ -0,0 +1,38 @@
+package tracerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestCodeOf(t *testing.T) {
+	if tracerr.WithCode(nil, "USER_NOT_FOUND") != nil {
+		t.Errorf("tracerr.WithCode(nil, ...) = non-nil; want nil")
+	}
+	if code, ok := tracerr.CodeOf(tracerr.New("some error")); code != "" || ok {
+		t.Errorf("tracerr.CodeOf() = %#v, %t; want \"\", false", code, ok)
+	}
+
+	err := tracerr.WithCode(errors.New("no rows"), "USER_NOT_FOUND")
+	wrapped := tracerr.Wrap(fmt.Errorf("failed to load user: %w", err), "failed to handle request")
+	if code, ok := tracerr.CodeOf(wrapped); code != "USER_NOT_FOUND" || !ok {
+		t.Errorf("tracerr.CodeOf() = %#v, %t; want \"USER_NOT_FOUND\", true", code, ok)
+	}
+	outer := tracerr.WithCode(wrapped, "REQUEST_FAILED")
+	if code, ok := tracerr.CodeOf(outer); code != "REQUEST_FAILED" || !ok {
+		t.Errorf("tracerr.CodeOf() = %#v, %t; want \"REQUEST_FAILED\", true", code, ok)
+	}
+
+	b, jsonErr := json.Marshal(wrapped)
+	if jsonErr != nil {
+		t.Fatalf("json.Marshal() error: %s", jsonErr)
+	}
+	if !strings.HasSuffix(string(b), `,"code":"USER_NOT_FOUND"}`) {
+		t.Errorf("json.Marshal() = %s; want code", b)
+	}
+}
@@ -0,0 +1,48 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestCollapseSameFile(t *testing.T) {
+	frames := []tracerr.Frame{
+		{Func: "main.parse", Line: 30, Path: "/src/read.go"},
+		{Func: "main.readFile", Line: 42, Path: "/src/read.go"},
+		{Func: "main.read", Line: 12, Path: "/src/read.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+		{Func: "main.init", Line: 3, Path: "/src/read.go"},
+	}
+	err := tracerr.CustomError(errors.New("some error"), frames)
+	defer func() {
+		tracerr.CollapseSameFile = false
+	}()
+
+	tracerr.CollapseSameFile = true
+	expected := "some error\n" +
+		"\t/src/read.go\n" +
+		"\t\t:30 main.parse()\n" +
+		"\t\t:42 main.readFile()\n" +
+		"\t\t:12 main.read()\n" +
+		"\t/src/main.go:7 main.main()\n" +
+		"\t/src/read.go:3 main.init()"
+	if err.Error() != expected {
+		t.Errorf("err.Error() = %#v; want %#v", err.Error(), expected)
+	}
+	if len(err.StackTrace()) != len(frames) {
+		t.Errorf("len(err.StackTrace()) = %d; want %d", len(err.StackTrace()), len(frames))
+	}
+
+	tracerr.CollapseSameFile = false
+	expected = "some error\n" +
+		"\t/src/read.go:30 main.parse()\n" +
+		"\t/src/read.go:42 main.readFile()\n" +
+		"\t/src/read.go:12 main.read()\n" +
+		"\t/src/main.go:7 main.main()\n" +
+		"\t/src/read.go:3 main.init()"
+	if err.Error() != expected {
+		t.Errorf("err.Error() = %#v; want %#v", err.Error(), expected)
+	}
+}
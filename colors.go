@@ -14,6 +14,10 @@ func bold(in string) string {
 	return color(1, in)
 }
 
+func dim(in string) string {
+	return color(2, in)
+}
+
 func black(in string) string {
 	return color(30, in)
 }
@@ -22,6 +26,18 @@ func red(in string) string {
 	return color(31, in)
 }
 
+func green(in string) string {
+	return color(32, in)
+}
+
 func yellow(in string) string {
 	return color(33, in)
 }
+
+func blue(in string) string {
+	return color(34, in)
+}
+
+func cyan(in string) string {
+	return color(36, in)
+}
@@ -0,0 +1,91 @@
+package tracerr_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestCompactFrames(t *testing.T) {
+	defer func() {
+		tracerr.CompactFrames = false
+	}()
+
+	var errs []tracerr.Error
+	for _, compact := range []bool{false, true} {
+		tracerr.CompactFrames = compact
+		errs = append(errs, tracerr.Wrap(addFrameA("some error"), "failed"))
+	}
+	tracerr.CompactFrames = false
+	expected, compact := errs[0], errs[1]
+	if !reflect.DeepEqual(compact.StackTrace(), expected.StackTrace()) {
+		t.Errorf("compact.StackTrace() = %#v; want %#v", compact.StackTrace(), expected.StackTrace())
+	}
+	if compact.Error() != expected.Error() {
+		t.Errorf("compact.Error() = %#v; want %#v", compact.Error(), expected.Error())
+	}
+	if s, e := fmt.Sprintf("%.2v", compact), fmt.Sprintf("%.2v", expected); s != e {
+		t.Errorf("fmt.Sprintf(compact) = %#v; want %#v", s, e)
+	}
+	trimmed := tracerr.Trim(compact, 1)
+	if !reflect.DeepEqual(trimmed.StackTrace(), expected.StackTrace()[1:]) {
+		t.Errorf("trimmed.StackTrace() = %#v; want %#v", trimmed.StackTrace(), expected.StackTrace()[1:])
+	}
+	wrapped := tracerr.Errorf("wrapped: %w", compact)
+	if !reflect.DeepEqual(wrapped.StackTrace(), expected.StackTrace()) {
+		t.Errorf("wrapped.StackTrace() = %#v; want %#v", wrapped.StackTrace(), expected.StackTrace())
+	}
+}
+
+func TestCompactFramesSettingsSnapshot(t *testing.T) {
+	defer func() {
+		tracerr.CompactFrames = false
+		tracerr.SkipPaths = nil
+	}()
+
+	var errs []tracerr.Error
+	for _, compact := range []bool{false, true} {
+		tracerr.CompactFrames = compact
+		errs = append(errs, tracerr.Wrap(addFrameA("some error"), ""))
+	}
+	tracerr.CompactFrames = false
+	expected, compact := errs[0], errs[1]
+	// Settings changed after an error is created do not affect its frames.
+	tracerr.SkipPaths = []string{"*_test.go"}
+	frames := compact.StackTrace()
+	if !reflect.DeepEqual(frames, expected.StackTrace()) {
+		t.Errorf("compact.StackTrace() = %#v; want %#v", frames, expected.StackTrace())
+	}
+	if again := compact.StackTrace(); &again[0] != &frames[0] {
+		t.Errorf("compact.StackTrace() is resolved again; want cached frames")
+	}
+}
+
+func TestCompactFramesStats(t *testing.T) {
+	defer func() {
+		tracerr.CompactFrames = false
+		tracerr.DisableCounting()
+		tracerr.ResetErrorCounts()
+		tracerr.ResetDepthStats()
+	}()
+	tracerr.EnableCounting()
+	tracerr.EnableDepthStats()
+	tracerr.CompactFrames = true
+
+	err := tracerr.New("some error")
+	// Counting and depth stats do not resolve compact frames.
+	if tracerr.Resolved(err) {
+		t.Errorf("tracerr.Resolved(err) = true; want false")
+	}
+	if counts := tracerr.ErrorCounts(); counts["github.com/ztrue/tracerr_test"] != 1 {
+		t.Errorf("tracerr.ErrorCounts() = %#v; want 1 error of tracerr_test", counts)
+	}
+	if min, _, _, _ := tracerr.StackDepthStats(); min == 0 {
+		t.Errorf("tracerr.StackDepthStats() min = %#v; want depth recorded", min)
+	}
+	if len(err.StackTrace()) == 0 || !tracerr.Resolved(err) {
+		t.Errorf("err.StackTrace() = %#v; want frames", err.StackTrace())
+	}
+}
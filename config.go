@@ -0,0 +1,84 @@
+package tracerr
+
+import (
+	"context"
+	"errors"
+)
+
+// Config defines how stack trace is captured.
+// Zero value of a field means package default.
+type Config struct {
+	// Cap is a cap for frames array, see DefaultCap.
+	Cap int
+	// MaxFrames limits number of captured frames, zero means no limit.
+	MaxFrames int
+	// Filter returns false for frames which should be dropped.
+	Filter func(frame Frame) bool
+	// Disabled turns off capturing, errors are created with empty stack trace.
+	Disabled bool
+	// Until is a suffix of function name, such as "ServeHTTP",
+	// capturing stops at the first frame of this function, see CaptureUntil.
+	Until string
+}
+
+type configKey struct{}
+
+// defaultConfig returns config based on package defaults.
+func defaultConfig() *Config {
+	return &Config{
+		Cap:   DefaultCap,
+		Until: captureUntil,
+	}
+}
+
+// captureUntil is a default suffix of boundary function name.
+var captureUntil = ""
+
+// CaptureUntil makes stack capturing stop at the first frame of a function,
+// which name ends with funcSuffix, such as router's "ServeHTTP",
+// so framework frames below this boundary are dropped.
+// The boundary frame itself is always kept.
+// Pass empty funcSuffix to capture the whole stack again.
+// Like other package settings it should be changed on initialization.
+func CaptureUntil(funcSuffix string) {
+	captureUntil = funcSuffix
+}
+
+// ContextWithConfig returns a copy of ctx with config,
+// which is used by NewCtx and WrapCtx.
+func ContextWithConfig(ctx context.Context, config *Config) context.Context {
+	return context.WithValue(ctx, configKey{}, config)
+}
+
+// ConfigFromContext returns config stored by ContextWithConfig.
+// Package defaults are returned if there is no config in ctx.
+func ConfigFromContext(ctx context.Context) *Config {
+	config, ok := ctx.Value(configKey{}).(*Config)
+	if !ok || config == nil {
+		return defaultConfig()
+	}
+	c := *config
+	if c.Cap <= 0 {
+		c.Cap = DefaultCap
+	}
+	if c.Until == "" {
+		c.Until = captureUntil
+	}
+	return &c
+}
+
+// NewCtx creates new error with stacktrace captured according
+// to config stored in ctx, see ContextWithConfig.
+func NewCtx(ctx context.Context, message string) Error {
+	return traceConfig(ConfigFromContext(ctx), errors.New(message), "", 2)
+}
+
+// WrapCtx adds stacktrace to existing error according
+// to config stored in ctx, see ContextWithConfig.
+// It works the same way as Wrap otherwise.
+func WrapCtx(ctx context.Context, err error, message string) Error {
+	if err == nil && PanicOnNilWrap {
+		panicNilWrap("WrapCtx")
+	}
+	return wrapConfig(ConfigFromContext(ctx), err, message, 2)
+}
@@ -0,0 +1,88 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestConfigFromContext(t *testing.T) {
+	config := tracerr.ConfigFromContext(context.Background())
+	if config.Cap != tracerr.DefaultCap || config.MaxFrames != 0 || config.Disabled {
+		t.Errorf(
+			"tracerr.ConfigFromContext(ctx) = %#v; want package defaults",
+			config,
+		)
+	}
+	ctx := tracerr.ContextWithConfig(context.Background(), &tracerr.Config{MaxFrames: 3})
+	config = tracerr.ConfigFromContext(ctx)
+	if config.Cap != tracerr.DefaultCap || config.MaxFrames != 3 {
+		t.Errorf(
+			"tracerr.ConfigFromContext(ctx) = %#v; want MaxFrames 3 and default Cap",
+			config,
+		)
+	}
+}
+
+func TestNewCtx(t *testing.T) {
+	full := context.Background()
+	lean := tracerr.ContextWithConfig(context.Background(), &tracerr.Config{
+		MaxFrames: 1,
+	})
+	disabled := tracerr.ContextWithConfig(context.Background(), &tracerr.Config{
+		Disabled: true,
+	})
+	filtered := tracerr.ContextWithConfig(context.Background(), &tracerr.Config{
+		Filter: func(frame tracerr.Frame) bool {
+			return !strings.HasPrefix(frame.Func, "testing.")
+		},
+	})
+
+	fullErr := tracerr.NewCtx(full, "some error")
+	if len(fullErr.StackTrace()) < 2 {
+		t.Errorf(
+			"len(fullErr.StackTrace()) = %#v; want >= %#v",
+			len(fullErr.StackTrace()), 2,
+		)
+	}
+	for _, err := range []tracerr.Error{
+		tracerr.NewCtx(lean, "some error"),
+		tracerr.WrapCtx(lean, errors.New("some error"), ""),
+	} {
+		frames := err.StackTrace()
+		if len(frames) != 1 || frames[0].Func != "github.com/ztrue/tracerr_test.TestNewCtx" {
+			t.Errorf(
+				"err.StackTrace() = %#v; want a single TestNewCtx frame",
+				frames,
+			)
+		}
+	}
+	disabledErr := tracerr.WrapCtx(disabled, errors.New("some error"), "message")
+	if len(disabledErr.StackTrace()) != 0 || tracerr.Unwrap(disabledErr).Error() != "some error" {
+		t.Errorf(
+			"disabledErr = %#v; want error with no stack trace",
+			disabledErr,
+		)
+	}
+	filteredErr := tracerr.NewCtx(filtered, "some error")
+	for _, frame := range filteredErr.StackTrace() {
+		if strings.HasPrefix(frame.Func, "testing.") {
+			t.Errorf(
+				"filteredErr.StackTrace() = %#v; want no testing frames",
+				filteredErr.StackTrace(),
+			)
+		}
+	}
+	if len(filteredErr.StackTrace()) >= len(fullErr.StackTrace()) {
+		t.Errorf(
+			"len(filteredErr.StackTrace()) = %#v; want < %#v",
+			len(filteredErr.StackTrace()), len(fullErr.StackTrace()),
+		)
+	}
+	if tracerr.WrapCtx(full, nil, "") != nil {
+		t.Errorf("tracerr.WrapCtx(ctx, nil, \"\") = non-nil; want nil")
+	}
+}
@@ -0,0 +1,35 @@
+package tracerr
+
+import (
+	"context"
+	"errors"
+)
+
+// WrapContext adds stacktrace to existing error the same way as Wrap,
+// but if err is context.Canceled or context.DeadlineExceeded,
+// the result is marked the same way as by Quiet and NoSource,
+// since cancellations are expected and rarely need a full stack trace.
+// Stack trace is still available by StackTrace, Print and Sprint functions.
+//
+// Not to be confused with WrapCtx, which captures stack trace
+// according to config stored in context.
+func WrapContext(err error, message string) Error {
+	w := wrap(err, message, 2)
+	e, ok := w.(*errorData)
+	if !ok || !IsContextError(err) {
+		return w
+	}
+	if e == err {
+		e = e.clone()
+	}
+	e.quiet = true
+	e.noSource = true
+	return e
+}
+
+// IsContextError checks if err or any error in its chain
+// is context.Canceled or context.DeadlineExceeded,
+// so a handler can log it quietly.
+func IsContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
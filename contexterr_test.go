@@ -0,0 +1,55 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestWrapContext(t *testing.T) {
+	for _, sentinel := range []error{context.Canceled, context.DeadlineExceeded} {
+		err := tracerr.WrapContext(fmt.Errorf("query: %w", sentinel), "failed to load")
+		if !tracerr.IsContextError(err) {
+			t.Errorf("tracerr.IsContextError(%#v) = false; want true", err)
+		}
+		if !errors.Is(err, sentinel) {
+			t.Errorf("errors.Is(err, %#v) = false; want true", sentinel)
+		}
+		if len(err.StackTrace()) == 0 {
+			t.Errorf("err.StackTrace() is empty; want stack trace")
+		}
+		expected := "failed to load\nquery: " + sentinel.Error()
+		if err.Error() != expected {
+			t.Errorf("err.Error() = %#v; want %#v", err.Error(), expected)
+		}
+		if output := tracerr.SprintSource(err); output != tracerr.Sprint(err) {
+			t.Errorf("tracerr.SprintSource() = %#v; want no source", output)
+		}
+	}
+
+	traced := tracerr.New("some error")
+	err := tracerr.WrapContext(traced, "failed to load")
+	if tracerr.IsContextError(err) {
+		t.Errorf("tracerr.IsContextError(%#v) = true; want false", err)
+	}
+	if err.Error() == "failed to load\nsome error" {
+		t.Errorf("err.Error() = %#v; want stack trace", err.Error())
+	}
+
+	ctxErr := tracerr.Wrap(context.Canceled, "")
+	if err := tracerr.WrapContext(ctxErr, ""); err == ctxErr {
+		t.Errorf("tracerr.WrapContext() returned the same error; want a copy")
+	}
+	if ctxErr.Error() == context.Canceled.Error() {
+		t.Errorf("original error is modified")
+	}
+	if err := tracerr.WrapContext(nil, "message"); err != nil {
+		t.Errorf("tracerr.WrapContext(nil) = %#v; want nil", err)
+	}
+	if tracerr.IsContextError(errors.New("some error")) || tracerr.IsContextError(nil) {
+		t.Errorf("tracerr.IsContextError() = true; want false")
+	}
+}
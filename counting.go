@@ -0,0 +1,61 @@
+package tracerr
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	countingEnabled atomic.Bool
+	errorCounts     sync.Map // package path -> *atomic.Int64
+)
+
+// EnableCounting turns on counting of errors created by tracerr
+// by package of their origin, which is a package of TopFrame,
+// see ErrorCounts.
+func EnableCounting() {
+	countingEnabled.Store(true)
+}
+
+// ErrorCounts returns number of errors created by tracerr
+// in each package since EnableCounting or ResetErrorCounts is called.
+func ErrorCounts() map[string]int64 {
+	counts := map[string]int64{}
+	errorCounts.Range(func(key, value interface{}) bool {
+		counts[key.(string)] = value.(*atomic.Int64).Load()
+		return true
+	})
+	return counts
+}
+
+// ResetErrorCounts sets all counts returned by ErrorCounts to zero.
+func ResetErrorCounts() {
+	errorCounts.Range(func(key, value interface{}) bool {
+		errorCounts.Delete(key)
+		return true
+	})
+}
+
+// countError counts an error by package of its origin
+// if EnableCounting is called.
+func countError(e *errorData) {
+	if !countingEnabled.Load() {
+		return
+	}
+	var frame Frame
+	var ok bool
+	if e.lazy != nil {
+		frame, ok = e.lazy.top()
+	} else {
+		frame, ok = TopFrame(e)
+	}
+	if !ok {
+		return
+	}
+	pkg := funcPackage(frame.Func)
+	counter, ok := errorCounts.Load(pkg)
+	if !ok {
+		counter, _ = errorCounts.LoadOrStore(pkg, new(atomic.Int64))
+	}
+	counter.(*atomic.Int64).Add(1)
+}
@@ -0,0 +1,40 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestErrorCounts(t *testing.T) {
+	defer func() {
+		tracerr.DisableCounting()
+		tracerr.ResetErrorCounts()
+	}()
+
+	tracerr.New("not counted")
+	if counts := tracerr.ErrorCounts(); len(counts) != 0 {
+		t.Errorf("tracerr.ErrorCounts() = %#v; want empty before EnableCounting", counts)
+	}
+
+	tracerr.EnableCounting()
+	for i := 0; i < 3; i++ {
+		tracerr.New("some error")
+	}
+	tracerr.Wrap(errors.New("some error"), "")
+	// Errors with disabled capturing are not counted, as well as custom errors.
+	tracerr.NewCtx(tracerr.ContextWithConfig(context.Background(), &tracerr.Config{Disabled: true}), "some error")
+	tracerr.CustomError(errors.New("some error"), nil)
+	counts := tracerr.ErrorCounts()
+	if len(counts) != 1 || counts["github.com/ztrue/tracerr_test"] != 4 {
+		t.Errorf("tracerr.ErrorCounts() = %#v; want 4 errors of tracerr_test", counts)
+	}
+
+	tracerr.ResetErrorCounts()
+	tracerr.New("some error")
+	if counts := tracerr.ErrorCounts(); counts["github.com/ztrue/tracerr_test"] != 1 {
+		t.Errorf("tracerr.ErrorCounts() = %#v; want 1 error after reset", counts)
+	}
+}
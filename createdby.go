@@ -0,0 +1,35 @@
+package tracerr
+
+import (
+	"runtime"
+	"strings"
+)
+
+// CaptureCreatedBy makes stack trace end with a frame of the place
+// where the current goroutine is created, marked as "created by"
+// in a panic output, so it is visible which goroutine spawned this one.
+//
+// It is expensive, since stack of the goroutine is dumped
+// as text by runtime.Stack and parsed for every error.
+var CaptureCreatedBy = false
+
+// createdBy returns a frame where the current goroutine is created.
+// It will be false for the main goroutine.
+func createdBy() (Frame, bool) {
+	buf := make([]byte, 4096)
+	for {
+		n := runtime.Stack(buf, false)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+	lines := strings.Split(string(buf), "\n")
+	for i := len(lines) - 2; i >= 0; i-- {
+		if strings.HasPrefix(lines[i], "created by ") {
+			return panicLogFrame(lines[i], lines[i+1]), true
+		}
+	}
+	return Frame{}, false
+}
@@ -0,0 +1,36 @@
+package tracerr_test
+
+import (
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func spawnError() tracerr.Error {
+	errs := make(chan tracerr.Error)
+	go func() {
+		errs <- tracerr.New("some error")
+	}()
+	return <-errs
+}
+
+func TestCaptureCreatedBy(t *testing.T) {
+	defer func() {
+		tracerr.CaptureCreatedBy = false
+	}()
+
+	frames := spawnError().StackTrace()
+	if last := frames[len(frames)-1]; last.Func != "runtime.goexit" {
+		t.Errorf("last frame = %#v; want runtime.goexit", last)
+	}
+
+	tracerr.CaptureCreatedBy = true
+	frames = spawnError().StackTrace()
+	last := frames[len(frames)-1]
+	if last.Func != "github.com/ztrue/tracerr_test.spawnError" || last.Line != 11 {
+		t.Errorf("last frame = %#v; want spawnError:11", last)
+	}
+	if frames[0].Func != "github.com/ztrue/tracerr_test.spawnError.func1" {
+		t.Errorf("frames[0] = %#v; want spawnError.func1", frames[0])
+	}
+}
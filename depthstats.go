@@ -0,0 +1,73 @@
+package tracerr
+
+import (
+	"sync/atomic"
+)
+
+// maxRecordedDepth is the largest depth recorded separately,
+// deeper stack traces are counted as of this depth.
+const maxRecordedDepth = 256
+
+var (
+	depthStatsEnabled atomic.Bool
+	depthCounts       [maxRecordedDepth + 1]atomic.Uint64
+)
+
+// EnableDepthStats turns on recording of number of frames
+// of every captured stack trace, see StackDepthStats.
+// Recording uses atomic counters only, so it is cheap enough for production.
+// With CompactFrames number of program counters is recorded,
+// so frames are not resolved for stats.
+func EnableDepthStats() {
+	depthStatsEnabled.Store(true)
+}
+
+// StackDepthStats returns minimum, maximum, median and 95th percentile
+// of number of frames of stack traces captured since EnableDepthStats,
+// which helps to choose DefaultCap, e.g. DefaultCap = p95.
+// Depths over 256 are counted as 256.
+// All values are zero if nothing is recorded.
+func StackDepthStats() (min, max, p50, p95 int) {
+	var counts [maxRecordedDepth + 1]uint64
+	var total uint64
+	for depth := range depthCounts {
+		counts[depth] = depthCounts[depth].Load()
+		total += counts[depth]
+	}
+	if total == 0 {
+		return 0, 0, 0, 0
+	}
+	min = -1
+	// Nearest-rank percentiles.
+	rank50 := (total*50 + 99) / 100
+	rank95 := (total*95 + 99) / 100
+	var cumulative uint64
+	for depth, count := range counts {
+		if count == 0 {
+			continue
+		}
+		if min < 0 {
+			min = depth
+		}
+		max = depth
+		if cumulative < rank50 && cumulative+count >= rank50 {
+			p50 = depth
+		}
+		if cumulative < rank95 && cumulative+count >= rank95 {
+			p95 = depth
+		}
+		cumulative += count
+	}
+	return min, max, p50, p95
+}
+
+// recordDepth records number of frames if EnableDepthStats is called.
+func recordDepth(depth int) {
+	if !depthStatsEnabled.Load() {
+		return
+	}
+	if depth > maxRecordedDepth {
+		depth = maxRecordedDepth
+	}
+	depthCounts[depth].Add(1)
+}
@@ -0,0 +1,43 @@
+package tracerr_test
+
+import (
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestStackDepthStats(t *testing.T) {
+	defer tracerr.ResetDepthStats()
+	tracerr.ResetDepthStats()
+
+	// Not recorded until enabled.
+	tracerr.RecordDepth(5)
+	if min, max, p50, p95 := tracerr.StackDepthStats(); min != 0 || max != 0 || p50 != 0 || p95 != 0 {
+		t.Errorf("tracerr.StackDepthStats() = %d, %d, %d, %d; want zeros", min, max, p50, p95)
+	}
+
+	tracerr.EnableDepthStats()
+	for depth := 100; depth >= 1; depth-- {
+		tracerr.RecordDepth(depth)
+	}
+	if min, max, p50, p95 := tracerr.StackDepthStats(); min != 1 || max != 100 || p50 != 50 || p95 != 95 {
+		t.Errorf("tracerr.StackDepthStats() = %d, %d, %d, %d; want 1, 100, 50, 95", min, max, p50, p95)
+	}
+
+	tracerr.ResetDepthStats()
+	tracerr.EnableDepthStats()
+	for _, depth := range []int{3, 3, 3, 8, 1000} {
+		tracerr.RecordDepth(depth)
+	}
+	if min, max, p50, p95 := tracerr.StackDepthStats(); min != 3 || max != 256 || p50 != 3 || p95 != 256 {
+		t.Errorf("tracerr.StackDepthStats() = %d, %d, %d, %d; want 3, 256, 3, 256", min, max, p50, p95)
+	}
+
+	tracerr.ResetDepthStats()
+	tracerr.EnableDepthStats()
+	err := addFrameA("some error")
+	depth := len(tracerr.StackTrace(err))
+	if min, max, _, _ := tracerr.StackDepthStats(); min != depth || max != depth {
+		t.Errorf("tracerr.StackDepthStats() = %d, %d; want %d", min, max, depth)
+	}
+}
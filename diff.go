@@ -0,0 +1,98 @@
+package tracerr
+
+import (
+	"strings"
+)
+
+// DiffIgnoreLines makes DiffFrames and SprintDiff compare frames
+// by function and path only, so frames differing by line are the same.
+var DiffIgnoreLines = false
+
+// frameKey identifies a frame for purpose of comparison.
+type frameKey struct {
+	fn   string
+	path string
+	line int
+}
+
+func keyOfFrame(frame Frame) frameKey {
+	key := frameKey{fn: frame.Func, path: frame.Path}
+	if !DiffIgnoreLines {
+		key.line = frame.Line
+	}
+	return key
+}
+
+// DiffFrames computes differences between stack traces a and b,
+// where onlyA are frames missing in b, onlyB are frames missing in a
+// and common are frames of a which are present in both.
+// Repeated frames are counted, order of frames is preserved.
+func DiffFrames(a, b []Frame) (onlyA, onlyB, common []Frame) {
+	counts := map[frameKey]int{}
+	for _, frame := range b {
+		counts[keyOfFrame(frame)]++
+	}
+	for _, frame := range a {
+		key := keyOfFrame(frame)
+		if counts[key] > 0 {
+			counts[key]--
+			common = append(common, frame)
+		} else {
+			onlyA = append(onlyA, frame)
+		}
+	}
+	counts = map[frameKey]int{}
+	for _, frame := range a {
+		counts[keyOfFrame(frame)]++
+	}
+	for _, frame := range b {
+		key := keyOfFrame(frame)
+		if counts[key] > 0 {
+			counts[key]--
+		} else {
+			onlyB = append(onlyB, frame)
+		}
+	}
+	return onlyA, onlyB, common
+}
+
+// SprintDiff returns a line by line difference between stack traces
+// of a and b, where frames only of a are prefixed with "-",
+// frames only of b are prefixed with "+" and common frames with " ".
+func SprintDiff(a, b error) string {
+	framesA := StackTrace(a)
+	framesB := StackTrace(b)
+	// Longest common subsequence of frames, lcs[i][j] is for suffixes.
+	lcs := make([][]int, len(framesA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(framesB)+1)
+	}
+	for i := len(framesA) - 1; i >= 0; i-- {
+		for j := len(framesB) - 1; j >= 0; j-- {
+			if keyOfFrame(framesA[i]) == keyOfFrame(framesB[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	lines := make([]string, 0, len(framesA)+len(framesB))
+	i, j := 0, 0
+	for i < len(framesA) || j < len(framesB) {
+		switch {
+		case i < len(framesA) && j < len(framesB) && keyOfFrame(framesA[i]) == keyOfFrame(framesB[j]):
+			lines = append(lines, " "+framesB[j].String())
+			i++
+			j++
+		case j == len(framesB) || (i < len(framesA) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "-"+framesA[i].String())
+			i++
+		default:
+			lines = append(lines, "+"+framesB[j].String())
+			j++
+		}
+	}
+	return strings.Join(lines, LineSeparator)
+}
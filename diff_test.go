@@ -0,0 +1,96 @@
+package tracerr_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+var (
+	diffRead     = tracerr.Frame{Func: "main.read", Line: 12, Path: "/src/read.go"}
+	diffReadFile = tracerr.Frame{Func: "main.readFile", Line: 42, Path: "/src/read.go"}
+	diffOpen     = tracerr.Frame{Func: "main.open", Line: 20, Path: "/src/read.go"}
+	diffMain     = tracerr.Frame{Func: "main.main", Line: 7, Path: "/src/main.go"}
+	diffWrite    = tracerr.Frame{Func: "main.write", Line: 9, Path: "/src/write.go"}
+)
+
+type DiffFramesTestCase struct {
+	A              []tracerr.Frame
+	B              []tracerr.Frame
+	IgnoreLines    bool
+	ExpectedOnlyA  []tracerr.Frame
+	ExpectedOnlyB  []tracerr.Frame
+	ExpectedCommon []tracerr.Frame
+}
+
+func TestDiffFrames(t *testing.T) {
+	movedMain := diffMain
+	movedMain.Line = 8
+	cases := []DiffFramesTestCase{
+		{
+			A:              []tracerr.Frame{diffReadFile, diffRead, diffMain},
+			B:              []tracerr.Frame{diffOpen, diffRead, diffMain},
+			ExpectedOnlyA:  []tracerr.Frame{diffReadFile},
+			ExpectedOnlyB:  []tracerr.Frame{diffOpen},
+			ExpectedCommon: []tracerr.Frame{diffRead, diffMain},
+		},
+		{
+			A:             []tracerr.Frame{diffRead},
+			B:             []tracerr.Frame{diffWrite},
+			ExpectedOnlyA: []tracerr.Frame{diffRead},
+			ExpectedOnlyB: []tracerr.Frame{diffWrite},
+		},
+		{
+			A:              []tracerr.Frame{diffRead, diffMain},
+			B:              []tracerr.Frame{diffRead, movedMain},
+			ExpectedOnlyA:  []tracerr.Frame{diffMain},
+			ExpectedOnlyB:  []tracerr.Frame{movedMain},
+			ExpectedCommon: []tracerr.Frame{diffRead},
+		},
+		{
+			A:              []tracerr.Frame{diffRead, diffMain},
+			B:              []tracerr.Frame{diffRead, movedMain},
+			IgnoreLines:    true,
+			ExpectedCommon: []tracerr.Frame{diffRead, diffMain},
+		},
+		{
+			A:              []tracerr.Frame{diffRead, diffRead, diffMain},
+			B:              []tracerr.Frame{diffRead, diffMain},
+			ExpectedOnlyA:  []tracerr.Frame{diffRead},
+			ExpectedCommon: []tracerr.Frame{diffRead, diffMain},
+		},
+	}
+
+	for i, c := range cases {
+		tracerr.DiffIgnoreLines = c.IgnoreLines
+		onlyA, onlyB, common := tracerr.DiffFrames(c.A, c.B)
+		tracerr.DiffIgnoreLines = false
+		if !reflect.DeepEqual(onlyA, c.ExpectedOnlyA) {
+			t.Errorf("cases[%#v]: onlyA = %#v; want %#v", i, onlyA, c.ExpectedOnlyA)
+		}
+		if !reflect.DeepEqual(onlyB, c.ExpectedOnlyB) {
+			t.Errorf("cases[%#v]: onlyB = %#v; want %#v", i, onlyB, c.ExpectedOnlyB)
+		}
+		if !reflect.DeepEqual(common, c.ExpectedCommon) {
+			t.Errorf("cases[%#v]: common = %#v; want %#v", i, common, c.ExpectedCommon)
+		}
+	}
+}
+
+func TestSprintDiff(t *testing.T) {
+	good := tracerr.CustomError(errors.New("good"), []tracerr.Frame{diffReadFile, diffRead, diffMain})
+	bad := tracerr.CustomError(errors.New("bad"), []tracerr.Frame{diffOpen, diffReadFile, diffWrite, diffMain})
+	expected := "+/src/read.go:20 main.open()\n" +
+		" /src/read.go:42 main.readFile()\n" +
+		"-/src/read.go:12 main.read()\n" +
+		"+/src/write.go:9 main.write()\n" +
+		" /src/main.go:7 main.main()"
+	if diff := tracerr.SprintDiff(good, bad); diff != expected {
+		t.Errorf("tracerr.SprintDiff() = %#v; want %#v", diff, expected)
+	}
+	if diff := tracerr.SprintDiff(errors.New("a"), errors.New("b")); diff != "" {
+		t.Errorf("tracerr.SprintDiff() = %#v; want empty", diff)
+	}
+}
@@ -0,0 +1,26 @@
+package tracerr
+
+import (
+	"errors"
+)
+
+// Equal checks if errors a and b are the same regardless of stack traces,
+// so they have the same messages (see Messages) and error message,
+// and the deepest errors in their chains (see Cause) match by errors.Is.
+// It is useful to compare errors in tests, where stack traces differ.
+func Equal(a, b error) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if errText(a) != errText(b) {
+		return false
+	}
+	causeA, causeB := Cause(a), Cause(b)
+	return errors.Is(causeA, causeB) || errors.Is(causeB, causeA)
+}
+
+// Equal checks if err is the same as other regardless of stack traces,
+// see Equal function.
+func (e *errorData) Equal(other error) bool {
+	return Equal(e, other)
+}
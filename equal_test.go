@@ -0,0 +1,49 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestEqual(t *testing.T) {
+	errNotFound := errors.New("not found")
+	newErr := func() error {
+		return tracerr.Wrap(errNotFound, "failed to read")
+	}
+	cyclic := &cyclicError{}
+	cyclic.next = &cyclicError{next: cyclic}
+	cases := []struct {
+		A        error
+		B        error
+		Expected bool
+	}{
+		{A: newErr(), B: newErr(), Expected: true},
+		{A: newErr(), B: tracerr.Wrap(tracerr.CustomError(errNotFound, nil), "failed to read"), Expected: true},
+		{A: newErr(), B: tracerr.Wrap(errNotFound, "failed to write"), Expected: false},
+		{A: newErr(), B: tracerr.Wrap(errors.New("not found"), "failed to read"), Expected: false},
+		{A: tracerr.Wrap(newErr(), "failed to load"), B: tracerr.Wrap(newErr(), "failed to load"), Expected: true},
+		{A: fmt.Errorf("load: %w", newErr()), B: fmt.Errorf("load: %w", newErr()), Expected: true},
+		{A: tracerr.Wrap(io.EOF, ""), B: io.EOF, Expected: true},
+		{A: newErr(), B: nil, Expected: false},
+		{A: nil, B: nil, Expected: true},
+		{A: tracerr.Wrap(cyclic, "failed"), B: tracerr.Wrap(cyclic, "failed"), Expected: true},
+		{A: tracerr.Wrap(cyclic, "failed"), B: newErr(), Expected: false},
+	}
+
+	for i, c := range cases {
+		if equal := tracerr.Equal(c.A, c.B); equal != c.Expected {
+			t.Errorf("cases[%#v]: tracerr.Equal(a, b) = %#v; want %#v", i, equal, c.Expected)
+		}
+		if equal := tracerr.Equal(c.B, c.A); equal != c.Expected {
+			t.Errorf("cases[%#v]: tracerr.Equal(b, a) = %#v; want %#v", i, equal, c.Expected)
+		}
+	}
+	a, b := tracerr.New("some error"), tracerr.New("some error")
+	if a.(interface{ Equal(error) bool }).Equal(b) {
+		t.Errorf("a.Equal(b) = true; want false for different errors.New")
+	}
+}
@@ -0,0 +1,32 @@
+// Package errgroupx adds stack trace of goroutine spawn site
+// to errors returned by goroutines of golang.org/x/sync/errgroup.
+//
+// It is a separate package, so programs which import tracerr do not import errgroup.
+package errgroupx
+
+import (
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ztrue/tracerr"
+)
+
+// Go calls fn in a new goroutine of g.
+//
+// If fn returns an error, stack trace of the place where Go is called
+// is appended to the stack trace of the error,
+// so it is visible which code spawned the goroutine.
+func Go(g *errgroup.Group, fn func() error) {
+	// Frames of the caller of Go, captured by the same rules as tracerr.New.
+	spawn := tracerr.Callers(1)
+	g.Go(func() error {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		frames := tracerr.StackTrace(err)
+		merged := make([]tracerr.Frame, 0, len(frames)+len(spawn))
+		merged = append(merged, frames...)
+		merged = append(merged, spawn...)
+		return tracerr.CustomError(err, merged)
+	})
+}
@@ -0,0 +1,64 @@
+package errgroupx_test
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ztrue/tracerr"
+	"github.com/ztrue/tracerr/errgroupx"
+)
+
+func TestGo(t *testing.T) {
+	regular := errors.New("regular error")
+	for _, returned := range []error{regular, tracerr.New("traced error")} {
+		var g errgroup.Group
+		spawnErrors(&g, returned)
+		err := g.Wait()
+		if !errors.Is(err, returned) {
+			t.Errorf("g.Wait() = %#v; want to wrap %#v", err, returned)
+		}
+		frames := tracerr.StackTrace(err)
+		found := false
+		for _, frame := range frames {
+			if frame.Func == "github.com/ztrue/tracerr/errgroupx_test.spawnErrors" {
+				found = true
+			}
+			if frame.Func == "github.com/ztrue/tracerr/errgroupx.Go" {
+				t.Errorf("tracerr.StackTrace(g.Wait()) = %#v; want no errgroupx.Go frame", frames)
+			}
+		}
+		if !found {
+			t.Errorf("tracerr.StackTrace(g.Wait()) = %#v; want spawnErrors frame", frames)
+		}
+	}
+
+	var g errgroup.Group
+	errgroupx.Go(&g, func() error {
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		t.Errorf("g.Wait() = %#v; want nil", err)
+	}
+}
+
+func spawnErrors(g *errgroup.Group, err error) {
+	errgroupx.Go(g, func() error {
+		return err
+	})
+}
+
+func TestGoSkipPaths(t *testing.T) {
+	tracerr.SkipPaths = []string{"*/errgroupx_test.go"}
+	defer func() {
+		tracerr.SkipPaths = nil
+	}()
+	var g errgroup.Group
+	spawnErrors(&g, errors.New("regular error"))
+	for _, frame := range tracerr.StackTrace(g.Wait()) {
+		if frame.Func == "github.com/ztrue/tracerr/errgroupx_test.spawnErrors" {
+			t.Errorf("frame = %#v; want skipped by SkipPaths", frame)
+		}
+	}
+}
@@ -5,9 +5,16 @@
 package tracerr
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"path/filepath"
 	"runtime"
+	"runtime/debug"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // DefaultCap is a default cap for frames array.
@@ -15,6 +22,74 @@ import (
 // for purpose of performance optimisation.
 var DefaultCap = 20
 
+// StackHeader is a line inserted between error message and stack trace
+// in Error() output, e.g. "--- stack trace ---".
+// Nothing is inserted if it is empty.
+var StackHeader = ""
+
+// ErrorPrefix is prepended to error message in output
+// of Error() and printers, e.g. "error: ".
+var ErrorPrefix = ""
+
+// LineSeparator separates lines of error output,
+// it can be changed to "\r\n" for tools which expect Windows line endings.
+var LineSeparator = "\n"
+
+// BaseNamesOnly makes frames display only a file name instead of a full path,
+// which makes output the same on different machines, e.g. for golden tests.
+var BaseNamesOnly = false
+
+// DedupMessage makes additional message omitted in output
+// if it is the same as the original error message.
+var DedupMessage = true
+
+// CollapseRepeatedMessages makes consecutive identical additional messages
+// displayed once with a number of repeats, e.g. "operation failed (×3)".
+// Messages returned by Messages are not changed.
+var CollapseRepeatedMessages = false
+
+// ShowInlined adds "[inlined]" mark to frames of inlined function calls.
+var ShowInlined = false
+
+// DeltaStacks makes Error() display stack traces added by WrapHere
+// only up to the frame where they diverge from the original stack trace,
+// since the rest of frames is the same. Frames are not changed.
+var DeltaStacks = false
+
+// CollapseSameFile makes Error() display consecutive frames of the same file
+// under a single path line, each with its line and function only,
+// which is shorter for code with many small functions in one file.
+// Frames are not changed.
+var CollapseSameFile = false
+
+// CompactFrames makes errors created by New, Wrap and similar functions
+// store program counters instead of frames, which are resolved once
+// on the first call of StackTrace, so errors which are never displayed
+// take less memory, e.g. 8 bytes per program counter instead of 48 bytes per frame.
+// Program counters are kept rather than a string of locations with an index,
+// since they are already captured and take less memory than any text.
+// Frames are filtered by SkipPaths and other settings as they were
+// when an error is created, resolving of a deep stack
+// can be limited by SymbolizeBudget.
+// It has no effect if CaptureCreatedBy is enabled.
+var CompactFrames = false
+
+// StrictCap makes DefaultCap a hard limit of captured frames,
+// so frames array is never reallocated.
+// Frames over the limit are dropped.
+var StrictCap = false
+
+// CaptureOwnModuleOnly makes stack capturing stop at the first frame
+// outside of the main module (detected via build info, see InApp),
+// since frames of standard library and frameworks are rarely actionable.
+// The top frame is always kept.
+var CaptureOwnModuleOnly = false
+
+// PanicOnNilWrap makes Wrap, Wrapf and WrapCtx panic if err is nil,
+// since wrapping of nil error is usually a missed check.
+// It is intended for tests and development, by default nil is returned.
+var PanicOnNilWrap = false
+
 // Error is an error with stack trace.
 type Error interface {
 	Error() string
@@ -25,40 +100,261 @@ type Error interface {
 type errorData struct {
 	// err contains original error.
 	err error
-	// optional additional message
-	message string
+	// optional additional messages, the outermost first
+	messages []string
 	// frames contains stack trace of an error.
 	frames []Frame
+	// annotations contains key-value pairs attached to an error.
+	annotations map[string]interface{}
+	// annotationKeys contains keys of annotations, the first added first.
+	annotationKeys []string
+	// status contains HTTP status code, zero if not set.
+	status int
+	// retries contains number of attempts, zero if not set.
+	retries int
+	// code contains a machine-readable error code, empty if not set.
+	code string
+	// time contains time when an error is created, zero if not set.
+	time time.Time
+	// lazy contains program counters of stack trace if CompactFrames is enabled,
+	// which are resolved to frames on demand.
+	lazy *lazyFrames
+	// recovered contains the original value of a recovered panic.
+	recovered interface{}
+	// panicked is true if an error is created from a recovered panic.
+	panicked bool
+	// quiet makes Error() omit stack trace.
+	quiet bool
+	// noSource makes source printers omit source fragments.
+	noSource bool
+	// stacks contains stack traces added by WrapHere, the latest last.
+	stacks [][]Frame
 }
 
 // CustomError creates an error with provided frames.
+// Trailing zero frames, e.g. of a buffer which is not filled, are dropped.
 func CustomError(err error, frames []Frame) Error {
+	for len(frames) > 0 && ZeroFrame(frames[len(frames)-1]) {
+		frames = frames[:len(frames)-1]
+	}
 	return &errorData{
 		err:    err,
 		frames: frames,
 	}
 }
 
+// New creates new error with stacktrace.
+func New(message string) Error {
+	return trace(errors.New(message), "", 2)
+}
+
+// NewInto creates new error with stacktrace the same way as New,
+// but frames are stored to buf, which grows if needed.
+// It returns buf with frames, which can be reused for the next error
+// to avoid allocation of frames in tight loops.
+//
+// Buffer is used by the error as is, so it must not be reused
+// while the error is still in use, otherwise its stack trace is overwritten.
+func NewInto(buf []Frame, message string) (Error, []Frame) {
+	e := &errorData{
+		err: errors.New(message),
+	}
+	e.frames = capture(defaultConfig(), 2, buf)
+	captured(e)
+	return e, e.frames
+}
+
+// Callers returns current stack trace as frames without creating an error,
+// e.g. for breadcrumbs or custom error types.
+// Frames are captured by the same rules as stack trace of New,
+// where skip is number of frames to skip, 0 means the caller of Callers.
+// Negative skip is the same as 0.
+func Callers(skip int) []Frame {
+	if skip < 0 {
+		skip = 0
+	}
+	return capture(defaultConfig(), skip+2, nil)
+}
+
+// NewLazy creates new error with stacktrace, where message is returned by fn,
+// which is called only once when message is needed for the first time.
+// It avoids cost of formatting message of an error which is never displayed.
+// It is safe to display an error from multiple goroutines.
+func NewLazy(fn func() string) Error {
+	return trace(&lazyError{fn: fn}, "", 2)
+}
+
+// lazyError is an error with message returned by a function.
+type lazyError struct {
+	once    sync.Once
+	fn      func() string
+	message string
+}
+
+func (e *lazyError) Error() string {
+	e.once.Do(func() {
+		e.message = e.fn()
+		e.fn = nil
+	})
+	return e.message
+}
+
 // Errorf creates new error with stacktrace and formatted message.
-// Formatting works the same way as in fmt.Errorf.
+// Formatting works the same way as in fmt.Errorf,
+// but errors created by tracerr are formatted without stack trace.
+//
+// If the only error wrapped by %w is created by tracerr,
+// its stack trace is kept the same way as by Wrap.
 func Errorf(message string, args ...interface{}) Error {
-	return trace(fmt.Errorf(message, args...), "", 2)
+	formatArgs := make([]interface{}, len(args))
+	for i, arg := range args {
+		if e, ok := arg.(*errorData); ok {
+			arg = textError{e: e}
+		}
+		formatArgs[i] = arg
+	}
+	err := fmt.Errorf(message, formatArgs...)
+	if wrapped, ok := errors.Unwrap(err).(textError); ok {
+		e := &errorData{
+			err:    err,
+			frames: wrapped.e.frames,
+			lazy:   wrapped.e.lazy,
+		}
+		captured(e)
+		return e
+	}
+	return trace(err, "", 2)
+}
+
+// textError displays an error created by tracerr without stack trace.
+type textError struct {
+	e *errorData
+}
+
+func (e textError) Error() string {
+	return e.e.text()
+}
+
+func (e textError) Unwrap() error {
+	return e.e
 }
 
 // Wrap adds stacktrace to existing error.
+// Empty or whitespace-only message is the same as no message.
+//
+// If err is already created by tracerr, stack trace is not changed
+// and message is added to messages of err, see Messages.
+// Stack trace is empty if err is one of BoringErrors.
 func Wrap(err error, message string) Error {
+	if err == nil && PanicOnNilWrap {
+		panicNilWrap("Wrap")
+	}
+	return wrap(err, message, 2)
+}
+
+// WrapHere adds message to err the same way as Wrap,
+// but if err already has stack trace, stack trace of the place
+// where WrapHere is called is added too, see Stacks.
+func WrapHere(err error, message string) Error {
+	e, ok := err.(*errorData)
+	if !ok {
+		return wrap(err, message, 2)
+	}
+	c := e.withMessage(message)
+	if c == e {
+		c = e.clone()
+	}
+	stacks := make([][]Frame, len(e.stacks), len(e.stacks)+1)
+	copy(stacks, e.stacks)
+	c.stacks = append(stacks, capture(defaultConfig(), 2, nil))
+	return c
+}
+
+// Stacks returns stack trace of err followed by stack traces added by WrapHere.
+// It will be empty if err is not of type Error.
+func Stacks(err error) [][]Frame {
+	if e, ok := err.(*errorData); ok {
+		return append([][]Frame{e.StackTrace()}, e.stacks...)
+	}
+	if e, ok := err.(Error); ok {
+		return [][]Frame{e.StackTrace()}
+	}
+	return nil
+}
+
+// Wrapf adds stacktrace to existing error with formatted message.
+// Formatting works the same way as in fmt.Sprintf.
+func Wrapf(err error, format string, a ...interface{}) Error {
 	if err == nil {
+		if PanicOnNilWrap {
+			panicNilWrap("Wrapf")
+		}
 		return nil
 	}
-	e, ok := err.(Error)
-	if ok {
+	return wrap(err, fmt.Sprintf(format, a...), 2)
+}
+
+// Ensure adds stacktrace to existing error if it has no one,
+// which is the same as Wrap with empty message.
+func Ensure(err error) Error {
+	return wrap(err, "", 2)
+}
+
+// panicNilWrap panics with location of the caller of wrapping function fn.
+func panicNilWrap(fn string) {
+	location := "unknown location"
+	if pc, path, line, ok := runtime.Caller(2); ok {
+		frame := Frame{Path: path, Line: line}
+		if f := runtime.FuncForPC(pc); f != nil {
+			frame.Func = f.Name()
+		}
+		location = frame.String()
+	}
+	panic(fmt.Sprintf("tracerr: %s called with nil error at %s", fn, location))
+}
+
+// wrap adds message to an error created by tracerr
+// or captures stack trace otherwise.
+func wrap(err error, message string, skip int) Error {
+	return wrapConfig(nil, err, message, skip+1)
+}
+
+// wrapConfig works the same way as wrap, but captures stack trace
+// with provided config, package defaults are used if config is nil.
+func wrapConfig(config *Config, err error, message string, skip int) Error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*errorData); ok {
+		return e.withMessage(message)
+	}
+	if e, ok := err.(Error); ok {
 		return e
 	}
-	return trace(err, message, 2)
+	if isBoring(err) {
+		return traceConfig(&Config{Disabled: true}, err, message, skip+1)
+	}
+	return traceConfig(config, err, message, skip+1)
 }
 
-func Wrapf(err error, format string, a ...interface{}) Error {
-	return Wrap(err, fmt.Sprintf(format, a...))
+// WrapDefer adds stacktrace to an error pointed by err, if it is not nil.
+// It works the same way as Wrap and is intended to be deferred
+// in a function with named error result:
+//
+//	func read() (err error) {
+//		defer tracerr.WrapDefer(&err, "failed to read")
+//		...
+//	}
+//
+// Note that the top frame points to the function which deferred WrapDefer
+// rather than to the place where the error is created,
+// its line is usually where the function returns,
+// but it may be a closing brace of the function depending on compiler.
+func WrapDefer(err *error, message string) {
+	if err == nil || *err == nil {
+		return
+	}
+	*err = wrap(*err, message, 2)
 }
 
 // Unwrap returns the original error.
@@ -73,29 +369,304 @@ func Unwrap(err error) error {
 	return e.Unwrap()
 }
 
+// Cause returns the deepest error in the chain of err,
+// which does not wrap any other error.
+// It will be nil if err is nil.
+func Cause(err error) error {
+	var cause error
+	walk(err, func(current error) bool {
+		cause = current
+		return true
+	})
+	return cause
+}
+
+// Messages returns additional messages of all errors in the chain of err,
+// the outermost first, excluding error messages.
+// It will be empty if there are no additional messages.
+func Messages(err error) []string {
+	var messages []string
+	walk(err, func(current error) bool {
+		if e, ok := current.(*errorData); ok {
+			messages = append(messages, e.messages...)
+		}
+		return true
+	})
+	return messages
+}
+
+// HasMessage checks if any additional message in the chain of err
+// contains substr, see Messages. Error messages are not checked.
+// It will be false if err is not created by tracerr.
+func HasMessage(err error, substr string) bool {
+	for _, message := range Messages(err) {
+		if strings.Contains(message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// RootMessage returns message of the deepest error in the chain of err,
+// without stack trace and additional messages.
+// It will be empty if err is nil.
+func RootMessage(err error) string {
+	cause := Cause(err)
+	if cause == nil {
+		return ""
+	}
+	return cause.Error()
+}
+
+// ErrorTypeName returns type name of the deepest error in the chain of err,
+// e.g. "*fs.PathError", which is useful to group errors by kind.
+// It will be empty if err is nil.
+func ErrorTypeName(err error) string {
+	cause := Cause(err)
+	if cause == nil {
+		return ""
+	}
+	return fmt.Sprintf("%T", cause)
+}
+
+// Merge combines errs into a single error by errors.Join,
+// so errors.Is and errors.As match any of them,
+// and adds stacktrace of the place where errors are merged.
+// Error message contains messages of all errs with their stack traces.
+//
+// Nil errors are dropped, it will be nil if there are no errors.
+func Merge(errs ...error) Error {
+	joined := errors.Join(errs...)
+	if joined == nil {
+		return nil
+	}
+	return trace(joined, "", 2)
+}
+
+// Errors returns errors joined by errors.Join or any other error
+// with Unwrap() []error method found in the chain of err.
+// Nested joined errors are flattened.
+//
+// It will be a single element slice with err if there are no joined errors
+// and nil if err is nil.
+func Errors(err error) []error {
+	return joinedErrors(err, 0, map[chainKey]bool{})
+}
+
+// joinedErrors flattens joined errors of err for Errors,
+// where visited contains joined errors already flattened in all chains,
+// so a joined error which contains itself is flattened once.
+func joinedErrors(err error, depth int, visited map[chainKey]bool) []error {
+	if err == nil {
+		return nil
+	}
+	var errs []error
+	found := false
+	walk(err, func(current error) bool {
+		joined, ok := current.(interface{ Unwrap() []error })
+		if !ok {
+			return true
+		}
+		found = true
+		if depth >= maxChainDepth {
+			return false
+		}
+		if key, ok := keyOf(current); ok {
+			if visited[key] {
+				return false
+			}
+			visited[key] = true
+		}
+		for _, child := range joined.Unwrap() {
+			errs = append(errs, joinedErrors(child, depth+1, visited)...)
+		}
+		return false
+	})
+	if !found {
+		return []error{err}
+	}
+	return errs
+}
+
 // Error returns error message.
 func (e *errorData) Error() string {
+	if e.quiet {
+		text := e.header()
+		return truncate(text, len(text))
+	}
+	return e.render(e.StackTrace(), 0, true)
+}
+
+// render returns error message with provided frames,
+// where location of each frame is padded to width.
+// Stack traces added by WrapHere are displayed if withStacks is true.
+func (e *errorData) render(frames []Frame, width int, withStacks bool) string {
+	text := e.header()
 	builder := strings.Builder{}
-	if e.message != "" {
-		builder.WriteString(e.message)
-		builder.WriteString("\n")
+	e.writeRendered(&builder, text, frames, width, withStacks)
+	return truncate(builder.String(), len(text))
+}
+
+// writeRendered writes output of render to w without truncation,
+// where text is error message.
+func (e *errorData) writeRendered(w textWriter, text string, frames []Frame, width int, withStacks bool) {
+	w.WriteString(text)
+	w.WriteString(LineSeparator)
+	if StackHeader != "" && len(frames) > 0 {
+		w.WriteString(StackHeader)
+		w.WriteString(LineSeparator)
+	}
+	writeFrames(w, frames, width)
+	if withStacks {
+		for _, stack := range e.stacks {
+			common := 0
+			if DeltaStacks {
+				common = commonTail(stack, e.StackTrace())
+				stack = stack[:len(stack)-common]
+			}
+			w.WriteString(LineSeparator)
+			w.WriteString("wrapped at:")
+			w.WriteString(LineSeparator)
+			writeFrames(w, stack, width)
+			if common > 0 {
+				if len(stack) > 0 {
+					w.WriteString(LineSeparator)
+				}
+				fmt.Fprintf(w, "\t... %d frames in common", common)
+			}
+		}
 	}
-	builder.WriteString(e.err.Error())
-	builder.WriteString("\n")
-	isFirstFrame := true
-	for _, frame := range e.StackTrace() {
-		if !isFirstFrame {
-			builder.WriteString("\n")
+}
+
+// writeFrames writes tab indented frames separated by LineSeparator.
+func writeFrames(w textWriter, frames []Frame, width int) {
+	for i := 0; i < len(frames); i++ {
+		if i > 0 {
+			w.WriteString(LineSeparator)
+		}
+		w.WriteString("\t")
+		end := i + 1
+		if CollapseSameFile && !frames[i].unresolved() {
+			for end < len(frames) && frames[end].Path == frames[i].Path && !frames[end].unresolved() {
+				end++
+			}
+		}
+		if end-i == 1 {
+			w.WriteString(frames[i].format(width))
+			continue
 		}
-		isFirstFrame = false
-		builder.WriteString("\t")
-		builder.WriteString(frame.String())
+		w.WriteString(frames[i].displayPath())
+		for _, frame := range frames[i:end] {
+			w.WriteString(LineSeparator)
+			fmt.Fprintf(w, "\t\t%-*s %s", width, ":"+strconv.Itoa(frame.Line), frame.displayFunc())
+		}
+		i = end - 1
+	}
+}
+
+// commonTail returns number of the last frames which are the same in a and b.
+func commonTail(a, b []Frame) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
 	}
+	return n
+}
+
+// Format implements fmt.Formatter:
+//
+//	%v, %s  error message with stack trace, the same as Error()
+//	        or error message only for an error returned by Quiet
+//	%.3v    error message with the first 3 frames only
+//	%.0v    error message without stack trace
+//	%40v    frame locations are padded to 40 characters to align functions
+//	%q      double-quoted Error()
+func (e *errorData) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v', 's':
+		all := e.StackTrace()
+		frames := all
+		width, _ := s.Width()
+		if precision, ok := s.Precision(); ok || e.quiet {
+			if precision <= 0 {
+				text := e.header()
+				io.WriteString(s, truncate(text, len(text)))
+				return
+			}
+			if precision < len(frames) {
+				frames = frames[:precision]
+			}
+		}
+		io.WriteString(s, e.render(frames, width, len(frames) == len(all)))
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(%s)", verb, e.Error())
+	}
+}
+
+// header returns text with ErrorPrefix, which is the first line of output.
+func (e *errorData) header() string {
+	return ErrorPrefix + e.text()
+}
+
+// text returns additional message and original error message without stack trace.
+func (e *errorData) text() string {
+	text := errText(e.err)
+	if len(e.messages) == 0 {
+		return text
+	}
+	builder := strings.Builder{}
+	for i := 0; i < len(e.messages); i++ {
+		message := e.messages[i]
+		if DedupMessage && message == text {
+			continue
+		}
+		builder.WriteString(message)
+		if CollapseRepeatedMessages {
+			n := 1
+			for i+n < len(e.messages) && e.messages[i+n] == message {
+				n++
+			}
+			if n > 1 {
+				fmt.Fprintf(&builder, " (×%d)", n)
+			}
+			i += n - 1
+		}
+		builder.WriteString(LineSeparator)
+	}
+	builder.WriteString(text)
 	return builder.String()
 }
 
+// errText returns error message without stack trace
+// if err is created by tracerr.
+// Stack trace of an error created by tracerr is also dropped
+// from message of an error which wraps it, e.g. by fmt.Errorf with %w,
+// so it is not displayed twice.
+func errText(err error) string {
+	if e, ok := err.(*errorData); ok {
+		return e.text()
+	}
+	text := err.Error()
+	// Joined errors are displayed with their stack traces, see Merge.
+	var inner *errorData
+	walk(errors.Unwrap(err), func(current error) bool {
+		inner, _ = current.(*errorData)
+		return inner == nil
+	})
+	// Stack trace is rendered only if message of inner error is embedded.
+	if inner == nil || inner.quiet || !strings.Contains(text, inner.header()) {
+		return text
+	}
+	return strings.Replace(text, inner.Error(), inner.text(), 1)
+}
+
 // StackTrace returns stack trace of an error.
 func (e *errorData) StackTrace() []Frame {
+	if e.lazy != nil {
+		return e.lazy.resolve()
+	}
 	return e.frames
 }
 
@@ -104,14 +675,102 @@ func (e *errorData) Unwrap() error {
 	return e.err
 }
 
+// Is reports whether target is an error created by tracerr
+// with the same original error, so copies of an error returned
+// by Wrap, Trim, Annotate and similar functions match each other.
+//
+// Original errors are compared by errors.Is rather than ==,
+// which panics for errors of non-comparable types,
+// such as structs with slice fields.
+// Such errors never match each other, so a copy of an error
+// with non-comparable original error matches only itself.
+func (e *errorData) Is(target error) bool {
+	t, ok := target.(*errorData)
+	if !ok {
+		return false
+	}
+	return e == t || errors.Is(e.err, t.err)
+}
+
+// Trim returns a copy of an error with the first n frames removed.
+// The original error is not modified.
+func (e *errorData) Trim(n int) Error {
+	if n < 0 {
+		n = 0
+	}
+	frames := e.StackTrace()
+	if n > len(frames) {
+		n = len(frames)
+	}
+	c := e.withoutPCs()
+	c.frames = make([]Frame, len(frames)-n)
+	copy(c.frames, frames[n:])
+	return c
+}
+
+// AppendFrame returns a copy of an error with frame added
+// to the top of stack trace, so it is the first one.
+// The original error is not modified.
+func (e *errorData) AppendFrame(frame Frame) Error {
+	frames := e.StackTrace()
+	c := e.withoutPCs()
+	c.frames = make([]Frame, 0, len(frames)+1)
+	c.frames = append(c.frames, frame)
+	c.frames = append(c.frames, frames...)
+	return c
+}
+
+// WithFrames returns a copy of an error with stack trace replaced by frames,
+// e.g. resolved later from program counters against debug info.
+// Messages, the original error and attached data are kept.
+// The original error is not modified.
+func (e *errorData) WithFrames(frames []Frame) Error {
+	c := e.withoutPCs()
+	c.frames = make([]Frame, len(frames))
+	copy(c.frames, frames)
+	return c
+}
+
+// withMessage returns a copy of an error with message added
+// as the outermost one, or the same error if message is empty.
+func (e *errorData) withMessage(message string) *errorData {
+	if strings.TrimSpace(message) == "" {
+		return e
+	}
+	c := e.clone()
+	c.messages = make([]string, 0, len(e.messages)+1)
+	c.messages = append(c.messages, message)
+	c.messages = append(c.messages, e.messages...)
+	return c
+}
+
+// withoutPCs returns a shallow copy of an error,
+// which frames are set instead of program counters.
+func (e *errorData) withoutPCs() *errorData {
+	c := e.clone()
+	c.lazy = nil
+	return c
+}
+
+// clone returns a shallow copy of an error.
+func (e *errorData) clone() *errorData {
+	c := *e
+	return &c
+}
+
 // Frame is a single step in stack trace.
 type Frame struct {
 	// Func contains a function name.
-	Func string
+	Func string `json:"func"`
 	// Line contains a line number.
-	Line int
+	Line int `json:"line"`
 	// Path contains a file path.
-	Path string
+	Path string `json:"path"`
+	// Inlined is true if a function call is inlined by compiler.
+	Inlined bool `json:"inlined,omitempty"`
+	// PC contains a program counter of a frame, which is not resolved,
+	// see SymbolizeBudget. It is zero for resolved frames.
+	PC uintptr `json:"pc,omitempty"`
 }
 
 // StackTrace returns stack trace of an error.
@@ -124,30 +783,373 @@ func StackTrace(err error) []Frame {
 	return e.StackTrace()
 }
 
+// TopFrame returns the first frame of a stack trace,
+// which is not a part of Go runtime.
+// It will be false if err is not of type Error or there is no such frame.
+func TopFrame(err error) (Frame, bool) {
+	return FindFrame(err, func(frame Frame) bool {
+		return !IsRuntime(frame)
+	})
+}
+
+// SameOrigin checks if a and b are created at the same place,
+// which is the same function, path and line of TopFrame.
+// It will be false if any of them has no such frame.
+func SameOrigin(a, b error) bool {
+	frameA, ok := TopFrame(a)
+	if !ok {
+		return false
+	}
+	frameB, ok := TopFrame(b)
+	if !ok {
+		return false
+	}
+	return frameA.Func == frameB.Func && frameA.Path == frameB.Path && frameA.Line == frameB.Line
+}
+
+// Trim returns a copy of an error with the first n frames removed.
+// It will be nil if err is not created by tracerr.
+func Trim(err error, n int) Error {
+	e, ok := err.(*errorData)
+	if !ok {
+		return nil
+	}
+	return e.Trim(n)
+}
+
+// AppendFrame returns a copy of an error with frame added
+// to the top of stack trace.
+// It will be nil if err is not created by tracerr.
+func AppendFrame(err error, frame Frame) Error {
+	e, ok := err.(*errorData)
+	if !ok {
+		return nil
+	}
+	return e.AppendFrame(frame)
+}
+
+// WithFrames returns a copy of an error with stack trace replaced by frames.
+// It will be nil if err is not created by tracerr.
+func WithFrames(err error, frames []Frame) Error {
+	e, ok := err.(*errorData)
+	if !ok {
+		return nil
+	}
+	return e.WithFrames(frames)
+}
+
 // String formats Frame to string.
 func (f Frame) String() string {
-	return fmt.Sprintf("%s:%d %s()", f.Path, f.Line, f.Func)
+	return f.format(0)
+}
+
+// format formats Frame to string, where location is padded to width.
+func (f Frame) format(width int) string {
+	if f.unresolved() {
+		return fmt.Sprintf("PC %#x", f.PC)
+	}
+	location := fmt.Sprintf("%s:%d", f.displayPath(), f.Line)
+	return fmt.Sprintf("%-*s %s", width, location, f.displayFunc())
+}
+
+// displayPath returns path of frame as it is displayed.
+func (f Frame) displayPath() string {
+	path := f.Path
+	if BaseNamesOnly && path != "" {
+		path = filepath.Base(path)
+	}
+	if path == "" {
+		path = "?"
+	}
+	return path
+}
+
+// displayFunc returns function of frame as it is displayed, with marks.
+func (f Frame) displayFunc() string {
+	// Frames of unknown program counters have no function name.
+	name := f.Func + "()"
+	if f.Func == "" {
+		name = "unknown()"
+	} else if strings.HasPrefix(f.Func, "_cgo_") {
+		name += " [cgo]"
+	}
+	if ShowInlined && f.Inlined {
+		name += " [inlined]"
+	}
+	return name
 }
 
 func trace(err error, message string, skip int) Error {
-	frames := make([]Frame, 0, DefaultCap)
+	return traceConfig(nil, err, message, skip+1)
+}
+
+// traceConfig captures stack trace with provided config,
+// package defaults are used if config is nil.
+func traceConfig(config *Config, err error, message string, skip int) Error {
+	if config == nil {
+		config = defaultConfig()
+	}
+	e := &errorData{
+		err: err,
+	}
+	if strings.TrimSpace(message) != "" {
+		e.messages = []string{message}
+	}
+	if config.Disabled {
+		return e
+	}
+	if CompactFrames && !CaptureCreatedBy {
+		e.lazy = &lazyFrames{
+			pcs:      capturePCs(config, skip+1),
+			resolver: newResolver(config),
+		}
+	} else {
+		e.frames = capture(config, skip+1, nil)
+	}
+	captured(e)
+	return e
+}
+
+// captured is called for every new error once stack trace is captured.
+func captured(e *errorData) {
+	if CaptureTime {
+		e.time = now()
+	}
+	if CaptureCreatedBy {
+		if frame, ok := createdBy(); ok {
+			e.frames = append(e.frames, frame)
+		}
+	}
+	if depthStatsEnabled.Load() {
+		// Compact frames are not resolved for stats,
+		// so depth is a number of program counters.
+		if e.lazy != nil {
+			recordDepth(len(e.lazy.pcs))
+		} else {
+			recordDepth(len(e.frames))
+		}
+	}
+	countError(e)
+	observe(e)
+}
+
+// capture returns stack trace skipping provided number of frames,
+// where 0 means capture itself.
+// Frames are stored to buf, which grows if needed,
+// or to a new array if buf is nil.
+func capture(config *Config, skip int, buf []Frame) []Frame {
+	r := newResolver(config)
+	return r.resolveFrames(capturePCs(config, skip+1), buf, 0)
+}
+
+// maxFramesOf returns limit of captured frames, 0 means no limit.
+func maxFramesOf(config *Config) int {
+	maxFrames := config.MaxFrames
+	if StrictCap && (maxFrames <= 0 || maxFrames > config.Cap) {
+		maxFrames = config.Cap
+	}
+	return maxFrames
+}
+
+// capturePCs returns program counters of stack skipping provided number
+// of frames, where 0 means capturePCs itself.
+func capturePCs(config *Config, skip int) []uintptr {
+	maxFrames := maxFramesOf(config)
+	size := config.Cap
+	if maxFrames > 0 && config.Filter == nil && len(SkipPaths) == 0 && len(registeredWrappers()) == 0 {
+		size = maxFrames
+	}
+	if size <= 0 {
+		size = 1
+	}
+	// Buffer is grown until it is not filled up,
+	// so no frames are lost for stacks deeper than cap.
 	for {
-		pc, path, line, ok := runtime.Caller(skip)
-		if !ok {
+		pcs := make([]uintptr, size)
+		n := runtime.Callers(skip+1, pcs)
+		if n < size || size == maxFrames {
+			return pcs[:n]
+		}
+		size *= 2
+	}
+}
+
+// resolver resolves program counters to frames by package settings
+// and config taken when stack trace is captured,
+// so settings changed later do not affect frames of existing errors.
+type resolver struct {
+	config        Config
+	maxFrames     int
+	wrappers      []string
+	skipPaths     []string
+	ownModuleOnly bool
+	runtimeAsm    bool
+	inApp         func(Frame) bool
+}
+
+func newResolver(config *Config) resolver {
+	return resolver{
+		config:        *config,
+		maxFrames:     maxFramesOf(config),
+		wrappers:      registeredWrappers(),
+		skipPaths:     SkipPaths,
+		ownModuleOnly: CaptureOwnModuleOnly,
+		runtimeAsm:    IncludeRuntimeAsm,
+		inApp:         InApp,
+	}
+}
+
+// isInApp checks if frame is in app the same way as Frame.InApp.
+func (r *resolver) isInApp(frame Frame) bool {
+	if r.inApp == nil {
+		return inMainModule(frame.Func)
+	}
+	return r.inApp(frame)
+}
+
+// resolveFrames returns frames of program counters.
+// Frames are stored to buf, which grows if needed,
+// or to a new array if buf is nil.
+// Program counters over positive budget are not resolved.
+func (r *resolver) resolveFrames(pcs []uintptr, buf []Frame, budget int) []Frame {
+	config := &r.config
+	frames := buf[:0]
+	if frames == nil {
+		frames = make([]Frame, 0, config.Cap)
+	}
+	if len(pcs) == 0 {
+		return frames
+	}
+	iterator := newFrameIterator(pcs, budget)
+	first := true
+	for r.maxFrames <= 0 || len(frames) < r.maxFrames {
+		frame, more := iterator.next()
+		// Frames over budget are unknown, so they are kept as is.
+		if frame.unresolved() {
+			frames = append(frames, frame)
+			if !more {
+				break
+			}
+			continue
+		}
+		if first && more && isWrapper(r.wrappers, frame.Func) {
+			continue
+		}
+		boundary := config.Until != "" && strings.HasSuffix(frame.Func, config.Until)
+		keep := boundary || (r.runtimeAsm && isRuntimeAsm(frame))
+		if r.ownModuleOnly && !first && !keep && !r.isInApp(frame) {
 			break
 		}
-		fn := runtime.FuncForPC(pc)
-		frame := Frame{
-			Func: fn.Name(),
-			Line: line,
-			Path: path,
+		first = false
+		if keep || (!skipPath(r.skipPaths, frame) && (config.Filter == nil || config.Filter(frame))) {
+			frames = append(frames, frame)
+		}
+		if boundary || !more {
+			break
 		}
-		frames = append(frames, frame)
-		skip++
 	}
-	return &errorData{
-		err:     err,
-		message: message,
-		frames:  frames,
+	return frames
+}
+
+// lazyFrames contains program counters of stack trace,
+// which are resolved to frames once on demand.
+type lazyFrames struct {
+	once     sync.Once
+	pcs      []uintptr
+	resolver resolver
+	frames   []Frame
+	// all contains all frames resolved regardless of SymbolizeBudget.
+	allOnce sync.Once
+	all     []Frame
+}
+
+// resolve returns frames of program counters, which are resolved on the first call.
+func (l *lazyFrames) resolve() []Frame {
+	l.once.Do(func() {
+		l.frames = l.resolver.resolveFrames(l.pcs, nil, SymbolizeBudget)
+	})
+	return l.frames
+}
+
+// top returns the first frame which is not a part of Go runtime the same way as TopFrame,
+// but program counters are resolved only up to it and frames are not stored.
+func (l *lazyFrames) top() (Frame, bool) {
+	r := l.resolver
+	filter := r.config.Filter
+	r.config.Filter = func(frame Frame) bool {
+		return !IsRuntime(frame) && (filter == nil || filter(frame))
+	}
+	r.maxFrames = 1
+	frames := r.resolveFrames(l.pcs, make([]Frame, 0, 1), 0)
+	if len(frames) == 0 || IsRuntime(frames[0]) {
+		return Frame{}, false
+	}
+	return frames[0], true
+}
+
+// resolveAll returns frames of program counters the same way as resolve,
+// but all of them are resolved.
+func (l *lazyFrames) resolveAll() []Frame {
+	frames := l.resolve()
+	if len(frames) == 0 || !frames[len(frames)-1].unresolved() {
+		return frames
+	}
+	l.allOnce.Do(func() {
+		l.all = l.resolver.resolveFrames(l.pcs, nil, 0)
+	})
+	return l.all
+}
+
+var (
+	mainModuleOnce sync.Once
+	mainModule     string
+	mainPackage    string
+)
+
+// mainModulePath returns path of the main module from build info,
+// it will be empty if build info is not available.
+func mainModulePath() string {
+	mainModuleOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			mainModule = info.Main.Path
+			if inModule(info.Path, mainModule) {
+				mainPackage = info.Path
+			}
+		}
+	})
+	return mainModule
+}
+
+// mainPackagePath returns import path of the main package from build info,
+// it will be empty if the main package is not a part of the main module,
+// e.g. in test binaries.
+func mainPackagePath() string {
+	mainModulePath()
+	return mainPackage
+}
+
+// inModule checks if package path is a part of module path.
+func inModule(pkg, module string) bool {
+	return module != "" && (pkg == module || strings.HasPrefix(pkg, module+"/"))
+}
+
+// inMainModule checks if function belongs to the main module.
+// Functions of package main are in the main module
+// if the main package belongs to it, since they are named "main.X".
+// It is always true if the main module is unknown.
+func inMainModule(fn string) bool {
+	mainModule := mainModulePath()
+	if mainModule == "" {
+		return true
+	}
+	if strings.HasPrefix(fn, "main.") && mainPackagePath() != "" {
+		return true
+	}
+	if !strings.HasPrefix(fn, mainModule) {
+		return false
 	}
+	rest := fn[len(mainModule):]
+	return strings.HasPrefix(rest, ".") ||
+		strings.HasPrefix(rest, "/") ||
+		strings.HasPrefix(rest, "_test.")
 }
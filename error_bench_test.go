@@ -1,7 +1,10 @@
 package tracerr_test
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"testing"
 
 	"github.com/ztrue/tracerr"
@@ -26,9 +29,164 @@ func BenchmarkNew(b *testing.B) {
 	}
 }
 
+func BenchmarkNewStrictCap(b *testing.B) {
+	tracerr.StrictCap = true
+	defer func() {
+		tracerr.StrictCap = false
+	}()
+	for _, frames := range []int{5, 10, 20, 40} {
+		suffix := fmt.Sprintf("%d", frames)
+		b.Run(suffix, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				addFrames(frames, "test error")
+			}
+		})
+	}
+}
+
+func BenchmarkNewInto(b *testing.B) {
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tracerr.New("test error")
+		}
+	})
+	b.Run("NewInto", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []tracerr.Frame
+		for i := 0; i < b.N; i++ {
+			_, buf = tracerr.NewInto(buf, "test error")
+		}
+	})
+}
+
+func BenchmarkNewOwnModuleOnly(b *testing.B) {
+	for _, ownOnly := range []bool{false, true} {
+		b.Run(fmt.Sprintf("%t", ownOnly), func(b *testing.B) {
+			tracerr.CaptureOwnModuleOnly = ownOnly
+			defer func() {
+				tracerr.CaptureOwnModuleOnly = false
+			}()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				addForeignFrames(20, "test error")
+			}
+		})
+	}
+}
+
+func BenchmarkNewFrameCache(b *testing.B) {
+	for _, cache := range []bool{false, true} {
+		b.Run(fmt.Sprintf("%t", cache), func(b *testing.B) {
+			if cache {
+				tracerr.EnableFrameCache()
+				defer tracerr.DisableFrameCache()
+			}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				addFrames(20, "test error")
+			}
+		})
+	}
+}
+
+func BenchmarkNewCompactFrames(b *testing.B) {
+	for _, compact := range []bool{false, true} {
+		b.Run(fmt.Sprintf("%t", compact), func(b *testing.B) {
+			tracerr.CompactFrames = compact
+			defer func() {
+				tracerr.CompactFrames = false
+			}()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				addFrames(20, "test error")
+			}
+		})
+	}
+}
+
+// BenchmarkWrap wraps an error without stack trace, which captures it,
+// and an error already created by tracerr, which only adds a message.
+// Capturing takes 5 allocations for a stack up to DefaultCap frames,
+// the same as New, wrapping of a traced error takes 2 allocations.
+func BenchmarkWrap(b *testing.B) {
+	err := errors.New("test error")
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tracerr.Wrap(err, "failed")
+		}
+	})
+	traced := tracerr.New("test error")
+	b.Run("Traced", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tracerr.Wrap(traced, "failed")
+		}
+	})
+}
+
+// BenchmarkError renders errors with stack traces of different depth,
+// where number of allocations grows by about 6 per frame.
+func BenchmarkError(b *testing.B) {
+	for _, frames := range []int{5, 10, 20, 40} {
+		err := addFrames(frames, "test error")
+		b.Run(fmt.Sprintf("%d", frames), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = err.Error()
+			}
+		})
+	}
+}
+
+// BenchmarkSprintSource renders an error with source fragments,
+// which are read from cache after the first call,
+// so it takes about 25 allocations per frame and no file reads.
+func BenchmarkSprintSource(b *testing.B) {
+	err := addFrames(10, "test error")
+	tracerr.SprintSource(err)
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tracerr.SprintSource(err)
+	}
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	err := addFrames(40, "test error")
+	b.Run("Error", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			io.WriteString(io.Discard, err.Error())
+		}
+	})
+	b.Run("WriteTo", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			err.(io.WriterTo).WriteTo(io.Discard)
+		}
+	})
+}
+
 func addFrames(depth int, message string) error {
 	if depth <= 1 {
 		return tracerr.New(message)
 	}
 	return addFrames(depth-1, message)
 }
+
+// addForeignFrames creates an error in a callback called by standard library
+// below depth frames of own module.
+func addForeignFrames(depth int, message string) error {
+	if depth > 1 {
+		return addForeignFrames(depth-1, message)
+	}
+	var err error
+	sort.Slice([]int{2, 1}, func(i, j int) bool {
+		err = tracerr.New(message)
+		return i < j
+	})
+	return err
+}
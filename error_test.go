@@ -23,7 +23,7 @@ func TestError(t *testing.T) {
 			ExpectedStackTrace: nil,
 		},
 		{
-			Error:              tracerr.Wrap(nil),
+			Error:              tracerr.Wrap(nil, ""),
 			ExpectedMessage:    "",
 			ExpectedStackTrace: nil,
 		},
@@ -50,7 +50,7 @@ func TestError(t *testing.T) {
 			},
 		},
 		{
-			Error:           tracerr.Wrap(errors.New("wrapped error")),
+			Error:           tracerr.Wrap(errors.New("wrapped error"), ""),
 			ExpectedMessage: "wrapped error",
 			ExpectedStackTrace: []tracerr.Frame{
 				{
@@ -87,7 +87,7 @@ func TestError(t *testing.T) {
 			},
 		},
 		{
-			Error:           tracerr.Wrap(addFrameA("error wrapped twice")),
+			Error:           tracerr.Wrap(addFrameA("error wrapped twice"), ""),
 			ExpectedMessage: "error wrapped twice",
 			ExpectedStackTrace: []tracerr.Frame{
 				{
@@ -122,10 +122,10 @@ func TestError(t *testing.T) {
 					i, c.ExpectedMessage,
 				)
 			}
-		} else if c.Error.Error() != c.ExpectedMessage {
+		} else if message := firstLine(c.Error.Error()); message != c.ExpectedMessage {
 			t.Errorf(
-				"cases[%#v].Error.Error() = %#v; want %#v",
-				i, c.Error.Error(), c.ExpectedMessage,
+				"firstLine(cases[%#v].Error.Error()) = %#v; want %#v",
+				i, message, c.ExpectedMessage,
 			)
 		}
 
@@ -177,7 +177,7 @@ func TestError(t *testing.T) {
 						prefix, j, frames[j].Line, expectedFrame.Line,
 					)
 				}
-				if !strings.HasSuffix(frames[j].Path, expectedFrame.Path) {
+				if !strings.HasSuffix(normalizePath(frames[j].Path), expectedFrame.Path) {
 					t.Errorf(
 						"%s[%#v].Path = %#v; want to has suffix %#v",
 						prefix, j, frames[j].Path, expectedFrame.Path,
@@ -205,10 +205,13 @@ func TestCustomError(t *testing.T) {
 	}
 	customErr := tracerr.CustomError(err, frames)
 	message := customErr.Error()
-	if message != err.Error() {
+	expectedMessage := "some error\n" +
+		"\t/src/github.com/john/doe/foobar.go:42 main.foo()\n" +
+		"\t/src/github.com/john/doe/bazqux.go:43 main.bar()"
+	if message != expectedMessage {
 		t.Errorf(
 			"customErr.Error() = %#v; want %#v",
-			message, err.Error(),
+			message, expectedMessage,
 		)
 	}
 	unwrapped := customErr.Unwrap()
@@ -293,7 +296,7 @@ func TestUnwrap(t *testing.T) {
 	for i, c := range cases {
 		err := c.Error
 		if c.Wrap {
-			err = tracerr.Wrap(err)
+			err = tracerr.Wrap(err, "")
 		}
 		unwrappedError := tracerr.Unwrap(err)
 		if unwrappedError != c.Error {
@@ -306,5 +309,846 @@ func TestUnwrap(t *testing.T) {
 }
 
 func wrapError(err error) error {
-	return tracerr.Wrap(err)
+	return tracerr.Wrap(err, "")
+}
+
+func TestTrim(t *testing.T) {
+	frames := []tracerr.Frame{
+		{Func: "main.foo", Line: 42, Path: "/src/github.com/john/doe/foobar.go"},
+		{Func: "main.bar", Line: 43, Path: "/src/github.com/john/doe/foobar.go"},
+		{Func: "main.baz", Line: 44, Path: "/src/github.com/john/doe/foobar.go"},
+	}
+	err := tracerr.CustomError(errors.New("some error"), frames)
+
+	for _, n := range []int{-1, 0, 1, 2, 3, 4} {
+		trimmed := tracerr.Trim(err, n)
+		expectedLen := len(frames) - n
+		if n < 0 {
+			expectedLen = len(frames)
+		} else if n > len(frames) {
+			expectedLen = 0
+		}
+		stackTrace := trimmed.StackTrace()
+		if len(stackTrace) != expectedLen {
+			t.Errorf(
+				"len(tracerr.Trim(err, %#v).StackTrace()) = %#v; want %#v",
+				n, len(stackTrace), expectedLen,
+			)
+			continue
+		}
+		offset := len(frames) - expectedLen
+		for i, frame := range stackTrace {
+			if frame != frames[offset+i] {
+				t.Errorf(
+					"tracerr.Trim(err, %#v).StackTrace()[%#v] = %#v; want %#v",
+					n, i, frame, frames[offset+i],
+				)
+			}
+		}
+		if trimmed.Unwrap() != err.Unwrap() {
+			t.Errorf(
+				"tracerr.Trim(err, %#v).Unwrap() = %#v; want %#v",
+				n, trimmed.Unwrap(), err.Unwrap(),
+			)
+		}
+	}
+
+	trimmed := tracerr.Trim(err, 1)
+	trimmed.StackTrace()[0].Line = 1337
+	if len(err.StackTrace()) != len(frames) || err.StackTrace()[1].Line != 43 {
+		t.Errorf(
+			"err.StackTrace() = %#v; want to be unchanged after Trim",
+			err.StackTrace(),
+		)
+	}
+
+	if tracerr.Trim(errors.New("regular error"), 1) != nil {
+		t.Errorf("tracerr.Trim(regular error, 1) = non-nil; want nil")
+	}
+}
+
+func TestTopFrame(t *testing.T) {
+	frames := []tracerr.Frame{
+		{Func: "runtime.gopanic", Line: 838, Path: "/usr/local/go/src/runtime/panic.go"},
+		{Func: "main.foo", Line: 42, Path: "/src/github.com/john/doe/foobar.go"},
+	}
+	frame, ok := tracerr.TopFrame(tracerr.CustomError(errors.New("some error"), frames))
+	if !ok || frame != frames[1] {
+		t.Errorf(
+			"tracerr.TopFrame(err) = %#v, %#v; want %#v, %#v",
+			frame, ok, frames[1], true,
+		)
+	}
+	frame, ok = tracerr.TopFrame(errors.New("regular error"))
+	if ok || frame != (tracerr.Frame{}) {
+		t.Errorf(
+			"tracerr.TopFrame(regular error) = %#v, %#v; want %#v, %#v",
+			frame, ok, tracerr.Frame{}, false,
+		)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	err3 := tracerr.New("error 3")
+	single := tracerr.Wrap(err1, "")
+
+	cases := []struct {
+		Error    error
+		Expected []error
+	}{
+		{
+			Error:    nil,
+			Expected: nil,
+		},
+		{
+			Error:    err1,
+			Expected: []error{err1},
+		},
+		{
+			Error:    single,
+			Expected: []error{single},
+		},
+		{
+			Error:    tracerr.Wrap(errors.Join(err1, err2), "validation failed"),
+			Expected: []error{err1, err2},
+		},
+		{
+			Error:    tracerr.Wrap(errors.Join(err1, nil, errors.Join(err2, err3)), ""),
+			Expected: []error{err1, err2, err3},
+		},
+		{
+			Error:    fmt.Errorf("wrapped: %w", errors.Join(err1, fmt.Errorf("%w", errors.Join(err2)))),
+			Expected: []error{err1, err2},
+		},
+	}
+
+	for i, c := range cases {
+		errs := tracerr.Errors(c.Error)
+		if len(errs) != len(c.Expected) {
+			t.Errorf(
+				"tracerr.Errors(cases[%#v].Error) = %#v; want %#v",
+				i, errs, c.Expected,
+			)
+			continue
+		}
+		for j, err := range errs {
+			if err != c.Expected[j] {
+				t.Errorf(
+					"tracerr.Errors(cases[%#v].Error)[%#v] = %#v; want %#v",
+					i, j, err, c.Expected[j],
+				)
+			}
+		}
+	}
+}
+
+func TestCause(t *testing.T) {
+	root := errors.New("root error")
+	cases := []struct {
+		Error           error
+		ExpectedCause   error
+		ExpectedMessage string
+	}{
+		{
+			Error:           nil,
+			ExpectedCause:   nil,
+			ExpectedMessage: "",
+		},
+		{
+			Error:           root,
+			ExpectedCause:   root,
+			ExpectedMessage: "root error",
+		},
+		{
+			Error:           tracerr.Wrap(root, "layer 1"),
+			ExpectedCause:   root,
+			ExpectedMessage: "root error",
+		},
+		{
+			Error: tracerr.Wrap(
+				fmt.Errorf("layer 2: %w", tracerr.Wrap(root, "layer 1")),
+				"layer 3",
+			),
+			ExpectedCause:   root,
+			ExpectedMessage: "root error",
+		},
+	}
+	for i, c := range cases {
+		cause := tracerr.Cause(c.Error)
+		if cause != c.ExpectedCause {
+			t.Errorf(
+				"tracerr.Cause(cases[%#v].Error) = %#v; want %#v",
+				i, cause, c.ExpectedCause,
+			)
+		}
+		message := tracerr.RootMessage(c.Error)
+		if message != c.ExpectedMessage {
+			t.Errorf(
+				"tracerr.RootMessage(cases[%#v].Error) = %#v; want %#v",
+				i, message, c.ExpectedMessage,
+			)
+		}
+	}
+}
+
+func TestStrictCap(t *testing.T) {
+	defaultCap := tracerr.DefaultCap
+	defer func() {
+		tracerr.DefaultCap = defaultCap
+		tracerr.StrictCap = false
+	}()
+	tracerr.DefaultCap = 2
+
+	err := addFrameA("some error").(tracerr.Error)
+	if len(err.StackTrace()) <= tracerr.DefaultCap {
+		t.Errorf(
+			"len(err.StackTrace()) = %#v; want > %#v",
+			len(err.StackTrace()), tracerr.DefaultCap,
+		)
+	}
+
+	tracerr.StrictCap = true
+	err = addFrameA("some error").(tracerr.Error)
+	frames := err.StackTrace()
+	if len(frames) != tracerr.DefaultCap || cap(frames) != tracerr.DefaultCap {
+		t.Errorf(
+			"len(err.StackTrace()), cap(err.StackTrace()) = %#v, %#v; want %#v, %#v",
+			len(frames), cap(frames), tracerr.DefaultCap, tracerr.DefaultCap,
+		)
+	}
+	if frames[0].Func != "github.com/ztrue/tracerr_test.addFrameC" {
+		t.Errorf(
+			"err.StackTrace()[0].Func = %#v; want %#v",
+			frames[0].Func, "github.com/ztrue/tracerr_test.addFrameC",
+		)
+	}
+}
+
+func TestStackHeader(t *testing.T) {
+	defer func() {
+		tracerr.StackHeader = ""
+	}()
+	frames := []tracerr.Frame{
+		{Func: "main.foo", Line: 42, Path: "/src/github.com/john/doe/foobar.go"},
+		{Func: "main.bar", Line: 43, Path: "/src/github.com/john/doe/bazqux.go"},
+	}
+	err := tracerr.CustomError(errors.New("some error"), frames)
+	cases := []struct {
+		StackHeader string
+		Error       tracerr.Error
+		Expected    string
+	}{
+		{
+			StackHeader: "",
+			Error:       err,
+			Expected: "some error\n" +
+				"\t/src/github.com/john/doe/foobar.go:42 main.foo()\n" +
+				"\t/src/github.com/john/doe/bazqux.go:43 main.bar()",
+		},
+		{
+			StackHeader: "--- stack trace ---",
+			Error:       err,
+			Expected: "some error\n" +
+				"--- stack trace ---\n" +
+				"\t/src/github.com/john/doe/foobar.go:42 main.foo()\n" +
+				"\t/src/github.com/john/doe/bazqux.go:43 main.bar()",
+		},
+		{
+			StackHeader: "--- stack trace ---",
+			Error:       tracerr.CustomError(errors.New("some error"), nil),
+			Expected:    "some error\n",
+		},
+	}
+	for i, c := range cases {
+		tracerr.StackHeader = c.StackHeader
+		if c.Error.Error() != c.Expected {
+			t.Errorf(
+				"cases[%#v].Error.Error() = %#v; want %#v",
+				i, c.Error.Error(), c.Expected,
+			)
+		}
+	}
+}
+
+func TestAppendFrame(t *testing.T) {
+	frames := []tracerr.Frame{
+		{Func: "main.foo", Line: 42, Path: "/src/github.com/john/doe/foobar.go"},
+		{Func: "main.bar", Line: 43, Path: "/src/github.com/john/doe/foobar.go"},
+	}
+	err := tracerr.CustomError(errors.New("some error"), frames)
+	boundary := tracerr.Frame{Func: "middleware.Handle", Line: 7, Path: "/src/middleware/handle.go"}
+
+	appended := tracerr.AppendFrame(err, boundary)
+	expected := append([]tracerr.Frame{boundary}, frames...)
+	stackTrace := appended.StackTrace()
+	if len(stackTrace) != len(expected) {
+		t.Fatalf(
+			"appended.StackTrace() = %#v; want %#v",
+			stackTrace, expected,
+		)
+	}
+	for i, frame := range expected {
+		if stackTrace[i] != frame {
+			t.Errorf(
+				"appended.StackTrace()[%#v] = %#v; want %#v",
+				i, stackTrace[i], frame,
+			)
+		}
+	}
+	if appended.Unwrap() != err.Unwrap() {
+		t.Errorf(
+			"appended.Unwrap() = %#v; want %#v",
+			appended.Unwrap(), err.Unwrap(),
+		)
+	}
+	if len(err.StackTrace()) != len(frames) || err.StackTrace()[0] != frames[0] {
+		t.Errorf(
+			"err.StackTrace() = %#v; want to be unchanged after AppendFrame",
+			err.StackTrace(),
+		)
+	}
+	if tracerr.AppendFrame(errors.New("regular error"), boundary) != nil {
+		t.Errorf("tracerr.AppendFrame(regular error, ...) = non-nil; want nil")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	frames := []tracerr.Frame{
+		{Func: "main.foo", Line: 42, Path: "/src/foobar.go"},
+		{Func: "main.bar", Line: 1337, Path: "/src/bazqux.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	}
+	err := tracerr.CustomError(errors.New("some error"), frames)
+	cases := []struct {
+		Format   string
+		Expected string
+	}{
+		{
+			Format: "%v",
+			Expected: "some error\n" +
+				"\t/src/foobar.go:42 main.foo()\n" +
+				"\t/src/bazqux.go:1337 main.bar()\n" +
+				"\t/src/main.go:7 main.main()",
+		},
+		{
+			Format:   "%s",
+			Expected: err.Error(),
+		},
+		{
+			Format:   "%.0v",
+			Expected: "some error",
+		},
+		{
+			Format: "%.2v",
+			Expected: "some error\n" +
+				"\t/src/foobar.go:42 main.foo()\n" +
+				"\t/src/bazqux.go:1337 main.bar()",
+		},
+		{
+			Format:   "%.5v",
+			Expected: err.Error(),
+		},
+		{
+			Format: "%20v",
+			Expected: "some error\n" +
+				"\t/src/foobar.go:42    main.foo()\n" +
+				"\t/src/bazqux.go:1337  main.bar()\n" +
+				"\t/src/main.go:7       main.main()",
+		},
+		{
+			Format: "%20.1v",
+			Expected: "some error\n" +
+				"\t/src/foobar.go:42    main.foo()",
+		},
+		{
+			Format:   "%q",
+			Expected: fmt.Sprintf("%q", err.Error()),
+		},
+		{
+			Format:   "%d",
+			Expected: "%!d(" + err.Error() + ")",
+		},
+	}
+	for _, c := range cases {
+		output := fmt.Sprintf(c.Format, err)
+		if output != c.Expected {
+			t.Errorf(
+				"fmt.Sprintf(%#v, err) = %#v; want %#v",
+				c.Format, output, c.Expected,
+			)
+		}
+	}
+}
+
+func TestDedupMessage(t *testing.T) {
+	defer func() {
+		tracerr.DedupMessage = true
+	}()
+	err := tracerr.Wrap(errors.New("not found"), "not found")
+	location := "error_test.go:" + fmt.Sprint(err.StackTrace()[0].Line) +
+		" github.com/ztrue/tracerr_test.TestDedupMessage()"
+
+	rows := strings.Split(err.Error(), "\n")
+	if rows[0] != "not found" || !strings.HasSuffix(rows[1], location) {
+		t.Errorf(
+			"err.Error() = %#v; want single \"not found\" followed by stack trace",
+			err.Error(),
+		)
+	}
+	rows = strings.Split(tracerr.Wrap(errors.New("not found"), "user").Error(), "\n")
+	if rows[0] != "user" || rows[1] != "not found" {
+		t.Errorf(
+			"rows = %#v; want \"user\" and \"not found\" rows",
+			rows,
+		)
+	}
+
+	tracerr.DedupMessage = false
+	rows = strings.Split(err.Error(), "\n")
+	if rows[0] != "not found" || rows[1] != "not found" || !strings.HasSuffix(rows[2], location) {
+		t.Errorf(
+			"err.Error() = %#v; want \"not found\" twice followed by stack trace",
+			err.Error(),
+		)
+	}
+}
+
+func TestWrapDefer(t *testing.T) {
+	tracerr.WrapDefer(nil, "message")
+
+	cases := []struct {
+		Path      int
+		ExpectNil bool
+		// Line of the top frame depends on compiler, e.g. it is different with -race.
+	}{
+		{Path: 0, ExpectNil: true},
+		{Path: 1},
+		{Path: 2},
+	}
+	for i, c := range cases {
+		err := multiReturn(c.Path)
+		if c.ExpectNil {
+			if err != nil {
+				t.Errorf("cases[%#v]: err = %#v; want nil", i, err)
+			}
+			continue
+		}
+		e, ok := err.(tracerr.Error)
+		if !ok {
+			t.Fatalf("cases[%#v]: err = %#v; want tracerr.Error", i, err)
+		}
+		if firstLine(e.Error()) != "operation failed" {
+			t.Errorf(
+				"cases[%#v]: firstLine(err.Error()) = %#v; want %#v",
+				i, firstLine(e.Error()), "operation failed",
+			)
+		}
+		frame := e.StackTrace()[0]
+		if frame.Func != "github.com/ztrue/tracerr_test.multiReturn" {
+			t.Errorf(
+				"cases[%#v]: err.StackTrace()[0] = %#v; want %#v",
+				i, frame, "multiReturn",
+			)
+		}
+	}
+
+	traced := tracerr.New("traced")
+	err := error(traced)
+	tracerr.WrapDefer(&err, "message")
+	if firstLine(err.Error()) != "message" || tracerr.StackTrace(err)[0] != traced.StackTrace()[0] {
+		t.Errorf("err = %#v; want traced with message", err)
+	}
+}
+
+func multiReturn(path int) (err error) {
+	defer tracerr.WrapDefer(&err, "operation failed")
+	switch path {
+	case 1:
+		return errors.New("first error")
+	case 2:
+		return errors.New("second error")
+	}
+	return nil
+}
+
+func TestWrapEmptyMessage(t *testing.T) {
+	regular := errors.New("regular error")
+	cases := []tracerr.Error{
+		tracerr.Ensure(regular),
+		tracerr.Wrap(regular, ""),
+		tracerr.Wrap(regular, " \t\n"),
+		tracerr.Wrapf(regular, ""),
+		tracerr.Wrapf(regular, "%s", " "),
+	}
+	for i, err := range cases {
+		rows := strings.Split(err.Error(), "\n")
+		if rows[0] != "regular error" || !strings.HasSuffix(rows[1], " github.com/ztrue/tracerr_test.TestWrapEmptyMessage()") {
+			t.Errorf(
+				"cases[%#v].Error() = %#v; want no message followed by stack trace",
+				i, err.Error(),
+			)
+		}
+	}
+
+	err := tracerr.Wrapf(regular, "failed %d", 42)
+	rows := strings.Split(err.Error(), "\n")
+	if rows[0] != "failed 42" || rows[1] != "regular error" {
+		t.Errorf("err.Error() = %#v; want message and error", err.Error())
+	}
+	if err.StackTrace()[0].Func != "github.com/ztrue/tracerr_test.TestWrapEmptyMessage" {
+		t.Errorf(
+			"err.StackTrace()[0].Func = %#v; want %#v",
+			err.StackTrace()[0].Func, "github.com/ztrue/tracerr_test.TestWrapEmptyMessage",
+		)
+	}
+	if tracerr.Ensure(nil) != nil || tracerr.Wrapf(nil, "message") != nil {
+		t.Errorf("tracerr.Ensure(nil), tracerr.Wrapf(nil, ...) = non-nil; want nil")
+	}
+	if tracerr.Ensure(err) != err {
+		t.Errorf("tracerr.Ensure(err) != err")
+	}
+}
+
+func TestMessages(t *testing.T) {
+	err := tracerr.Wrap(errors.New("root"), "inner")
+	err = tracerr.Wrap(err, "")
+	err = tracerr.Wrap(err, "middle")
+	err = tracerr.Wrapf(err, "outer %d", 1)
+	messages := tracerr.Messages(err)
+	expected := []string{"outer 1", "middle", "inner"}
+	if len(messages) != len(expected) {
+		t.Fatalf("Messages() = %#v; want %#v", messages, expected)
+	}
+	for i := range expected {
+		if messages[i] != expected[i] {
+			t.Errorf("Messages()[%d] = %#v; want %#v", i, messages[i], expected[i])
+		}
+	}
+	if text := tracerr.Sprint(err); !strings.HasPrefix(text, "outer 1\nmiddle\ninner\nroot\n") {
+		t.Errorf("Sprint() = %#v; want messages before error text", text)
+	}
+	if messages := tracerr.Messages(errors.New("plain")); len(messages) != 0 {
+		t.Errorf("Messages(plain) = %#v; want empty", messages)
+	}
+}
+
+func TestCaptureOwnModuleOnly(t *testing.T) {
+	tracerr.CaptureOwnModuleOnly = true
+	defer func() {
+		tracerr.CaptureOwnModuleOnly = false
+	}()
+
+	frames := tracerr.StackTrace(addFrameA("own"))
+	expected := []string{
+		"github.com/ztrue/tracerr_test.addFrameC",
+		"github.com/ztrue/tracerr_test.addFrameB",
+		"github.com/ztrue/tracerr_test.addFrameA",
+		"github.com/ztrue/tracerr_test.TestCaptureOwnModuleOnly",
+	}
+	if len(frames) != len(expected) {
+		t.Fatalf("len(frames) = %d; want %d: %#v", len(frames), len(expected), frames)
+	}
+	for i, fn := range expected {
+		if frames[i].Func != fn {
+			t.Errorf("frames[%d].Func = %#v; want %#v", i, frames[i].Func, fn)
+		}
+	}
+
+	// Stops right after the top frame called by standard library.
+	frames = tracerr.StackTrace(addForeignFrames(5, "foreign"))
+	if len(frames) != 1 || !strings.HasPrefix(frames[0].Func, "github.com/ztrue/tracerr_test.addForeignFrames.") {
+		t.Errorf("frames = %#v; want the top frame only", frames)
+	}
+}
+
+type nonComparableError struct {
+	fields []string
+}
+
+func (e nonComparableError) Error() string {
+	return strings.Join(e.fields, ", ")
+}
+
+func TestIs(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	err := tracerr.Wrap(sentinel, "")
+	if !errors.Is(tracerr.Wrap(err, "outer"), err) {
+		t.Errorf("errors.Is(wrapped, err) = false; want true")
+	}
+	if !errors.Is(tracerr.Trim(err, 1), sentinel) {
+		t.Errorf("errors.Is(trimmed, sentinel) = false; want true")
+	}
+	if errors.Is(err, tracerr.New("sentinel")) {
+		t.Errorf("errors.Is(err, other) = true; want false")
+	}
+}
+
+func TestIsNonComparable(t *testing.T) {
+	original := nonComparableError{fields: []string{"name", "email"}}
+	err := tracerr.Wrap(original, "")
+	wrapped := tracerr.Wrap(err, "invalid fields")
+	if !errors.Is(err, err) {
+		t.Errorf("errors.Is(err, err) = false; want true")
+	}
+	if errors.Is(wrapped, err) {
+		t.Errorf("errors.Is(wrapped, err) = true; want false")
+	}
+	if errors.Is(wrapped, nonComparableError{fields: []string{"name"}}) {
+		t.Errorf("errors.Is(wrapped, nonComparableError{}) = true; want false")
+	}
+	if errors.Is(wrapped, tracerr.Wrap(nonComparableError{fields: []string{"name"}}, "")) {
+		t.Errorf("errors.Is(wrapped, other) = true; want false")
+	}
+	var target nonComparableError
+	if !errors.As(wrapped, &target) || len(target.fields) != 2 {
+		t.Errorf("errors.As(wrapped) = %#v; want %#v", target, original)
+	}
+}
+
+func TestNewLazy(t *testing.T) {
+	calls := 0
+	err := tracerr.NewLazy(func() string {
+		calls++
+		return fmt.Sprintf("expensive %d", 42)
+	})
+	if calls != 0 {
+		t.Fatalf("calls = %#v; want %#v before rendering", calls, 0)
+	}
+	if len(err.StackTrace()) == 0 || err.StackTrace()[0].Func != "github.com/ztrue/tracerr_test.TestNewLazy" {
+		t.Errorf("err.StackTrace() = %#v; want captured eagerly", err.StackTrace())
+	}
+
+	done := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		go func() {
+			defer func() {
+				done <- struct{}{}
+			}()
+			if firstLine(err.Error()) != "expensive 42" {
+				t.Errorf("firstLine(err.Error()) = %#v; want %#v", firstLine(err.Error()), "expensive 42")
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+	tracerr.Sprint(err)
+	if calls != 1 {
+		t.Errorf("calls = %#v; want %#v", calls, 1)
+	}
+}
+
+func TestErrorTypeName(t *testing.T) {
+	cases := []struct {
+		Error    error
+		Expected string
+	}{
+		{
+			Error:    tracerr.Wrap(fmt.Errorf("context: %w", nonComparableError{fields: []string{"name"}}), "failed"),
+			Expected: "tracerr_test.nonComparableError",
+		},
+		{
+			Error:    tracerr.New("some error"),
+			Expected: "*errors.errorString",
+		},
+		{
+			Error:    fmt.Errorf("context: %w", tracerr.Wrap(&customError{}, "")),
+			Expected: "*tracerr_test.customError",
+		},
+		{
+			Error:    nil,
+			Expected: "",
+		},
+	}
+	for i, c := range cases {
+		if name := tracerr.ErrorTypeName(c.Error); name != c.Expected {
+			t.Errorf("cases[%#v]: tracerr.ErrorTypeName() = %#v; want %#v", i, name, c.Expected)
+		}
+	}
+}
+
+type customError struct{}
+
+func (e *customError) Error() string {
+	return "custom error"
+}
+
+func TestSameOrigin(t *testing.T) {
+	var errs []error
+	for i := 0; i < 2; i++ {
+		errs = append(errs, tracerr.Errorf("error %d", i))
+	}
+	other := tracerr.New("error 0")
+	if !tracerr.SameOrigin(errs[0], errs[1]) {
+		t.Errorf("tracerr.SameOrigin(errs[0], errs[1]) = false; want true")
+	}
+	if !tracerr.SameOrigin(tracerr.Wrap(errs[0], "wrapped"), errs[1]) {
+		t.Errorf("tracerr.SameOrigin(wrapped, errs[1]) = false; want true")
+	}
+	if tracerr.SameOrigin(errs[0], other) {
+		t.Errorf("tracerr.SameOrigin(errs[0], other) = true; want false")
+	}
+	// Callers are different, but both errors are created in addFrameC.
+	if !tracerr.SameOrigin(addFrameA("a"), addFrameB("b")) {
+		t.Errorf("tracerr.SameOrigin(addFrameA, addFrameB) = false; want true")
+	}
+	if tracerr.SameOrigin(errors.New("a"), errors.New("a")) {
+		t.Errorf("tracerr.SameOrigin(regular, regular) = true; want false")
+	}
+	if tracerr.SameOrigin(errs[0], tracerr.CustomError(errors.New("a"), nil)) {
+		t.Errorf("tracerr.SameOrigin(errs[0], no frames) = true; want false")
+	}
+}
+
+func TestNewInto(t *testing.T) {
+	buf := make([]tracerr.Frame, 0, 1)
+	err, buf := tracerr.NewInto(buf, "some error")
+	if firstLine(err.Error()) != "some error" {
+		t.Errorf("firstLine(err.Error()) = %#v; want %#v", firstLine(err.Error()), "some error")
+	}
+	frames := err.StackTrace()
+	if len(frames) == 0 || frames[0].Func != "github.com/ztrue/tracerr_test.TestNewInto" {
+		t.Fatalf("err.StackTrace() = %#v; want TestNewInto first", frames)
+	}
+	if len(buf) != len(frames) || &buf[0] != &frames[0] {
+		t.Errorf("buf = %#v; want frames of err", buf)
+	}
+	// Buffer is large enough now, so it is reused.
+	first := &buf[0]
+	_, buf = tracerr.NewInto(buf, "another error")
+	if &buf[0] != first {
+		t.Errorf("buf is reallocated; want reused")
+	}
+}
+
+func TestErrorfWrap(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	err := tracerr.Errorf("failed: %w", sentinel)
+	if !errors.Is(err, sentinel) {
+		t.Errorf("errors.Is(err, sentinel) = false; want true")
+	}
+	if frame := err.StackTrace()[0]; frame.Func != "github.com/ztrue/tracerr_test.TestErrorfWrap" {
+		t.Errorf("err.StackTrace()[0] = %#v; want TestErrorfWrap", frame)
+	}
+
+	inner := tracerr.Wrap(addFrameA("some error"), "inner")
+	err = tracerr.Errorf("failed: %w", inner)
+	if !errors.Is(err, inner) {
+		t.Errorf("errors.Is(err, inner) = false; want true")
+	}
+	var target tracerr.Error
+	if !errors.As(err, &target) {
+		t.Errorf("errors.As(err, tracerr.Error) = false; want true")
+	}
+	if frames := err.StackTrace(); len(frames) != len(inner.StackTrace()) || frames[0] != inner.StackTrace()[0] {
+		t.Errorf("err.StackTrace() = %#v; want stack trace of inner", frames)
+	}
+	expected := "failed: inner\nsome error"
+	if text := fmt.Sprintf("%.0v", err); text != expected {
+		t.Errorf("fmt.Sprintf(%%.0v) = %#v; want %#v", text, expected)
+	}
+	if rows := strings.Split(err.Error(), "\n"); len(rows) != len(inner.StackTrace())+2 {
+		t.Errorf("err.Error() = %#v; want stack trace once", err.Error())
+	}
+
+	// Errors are formatted without stack trace by other verbs too.
+	err = tracerr.Errorf("failed: %v", inner)
+	if text := fmt.Sprintf("%.0v", err); text != expected {
+		t.Errorf("fmt.Sprintf(%%.0v) = %#v; want %#v", text, expected)
+	}
+	if frame := err.StackTrace()[0]; frame.Func != "github.com/ztrue/tracerr_test.TestErrorfWrap" {
+		t.Errorf("err.StackTrace()[0] = %#v; want TestErrorfWrap", frame)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	first := addFrameA("first error")
+	second := tracerr.New("second error")
+	err := tracerr.Merge(first, nil, second)
+	if !errors.Is(err, first) || !errors.Is(err, second) {
+		t.Errorf("errors.Is(err, first/second) = false; want true")
+	}
+	if frame := err.StackTrace()[0]; frame.Func != "github.com/ztrue/tracerr_test.TestMerge" {
+		t.Errorf("err.StackTrace()[0] = %#v; want TestMerge", frame)
+	}
+	if errs := tracerr.Errors(err); len(errs) != 2 || errs[0] != first || errs[1] != second {
+		t.Errorf("tracerr.Errors(err) = %#v; want first and second", errs)
+	}
+	output := err.Error()
+	for _, expected := range []string{
+		"first error\n\t" + first.(tracerr.Error).StackTrace()[0].String(),
+		"second error\n\t" + second.StackTrace()[0].String(),
+		"\t" + err.StackTrace()[0].String(),
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("err.Error() = %#v; want it to contain %#v", output, expected)
+		}
+	}
+
+	if err := tracerr.Merge(nil, nil); err != nil {
+		t.Errorf("tracerr.Merge(nil, nil) = %#v; want nil", err)
+	}
+	if err := tracerr.Merge(); err != nil {
+		t.Errorf("tracerr.Merge() = %#v; want nil", err)
+	}
+}
+
+func TestWithFrames(t *testing.T) {
+	original := errors.New("some error")
+	frames := []tracerr.Frame{
+		{Func: "main.read", Line: 0, Path: "?"},
+	}
+	err := tracerr.Wrap(tracerr.CustomError(original, frames), "failed to read")
+	resolved := []tracerr.Frame{
+		{Func: "main.read", Line: 12, Path: "/build/read.go"},
+		{Func: "main.main", Line: 7, Path: "/build/main.go"},
+	}
+
+	replaced := tracerr.WithFrames(err, resolved)
+	resolved[0].Line = 13
+	expected := "failed to read\nsome error\n" +
+		"\t/build/read.go:12 main.read()\n" +
+		"\t/build/main.go:7 main.main()"
+	if replaced.Error() != expected {
+		t.Errorf("replaced.Error() = %#v; want %#v", replaced.Error(), expected)
+	}
+	if replaced.Unwrap() != original || !errors.Is(replaced, original) {
+		t.Errorf("replaced.Unwrap() = %#v; want %#v", replaced.Unwrap(), original)
+	}
+	if stackTrace := err.StackTrace(); len(stackTrace) != len(frames) || stackTrace[0] != frames[0] {
+		t.Errorf("err.StackTrace() = %#v; want to be unchanged after WithFrames", stackTrace)
+	}
+	if tracerr.WithFrames(errors.New("regular error"), resolved) != nil {
+		t.Errorf("tracerr.WithFrames(regular error, ...) = non-nil; want nil")
+	}
+}
+
+func TestWrapWrappedChain(t *testing.T) {
+	inner := errors.New("connection refused")
+	err := tracerr.Wrap(fmt.Errorf("dial db: %w", inner), "failed to connect")
+	text := err.Error()
+	if strings.Count(text, "connection refused") != 1 {
+		t.Errorf("err.Error() = %#v; want inner error text once", text)
+	}
+	if !strings.HasPrefix(text, "failed to connect\ndial db: connection refused\n") {
+		t.Errorf("err.Error() = %#v; want message and wrapped error text", text)
+	}
+
+	traced := tracerr.New("connection refused")
+	err = tracerr.Wrap(fmt.Errorf("dial db: %w", traced), "failed to connect")
+	text = err.Error()
+	if strings.Count(text, "connection refused") != 1 {
+		t.Errorf("err.Error() = %#v; want inner error text once", text)
+	}
+	if strings.Count(text, "TestWrapWrappedChain") != 1 {
+		t.Errorf("err.Error() = %#v; want a single stack trace", text)
+	}
+	if !strings.HasPrefix(text, "failed to connect\ndial db: connection refused\n") {
+		t.Errorf("err.Error() = %#v; want message and wrapped error text", text)
+	}
+	if !errors.Is(err, traced) {
+		t.Errorf("errors.Is(err, traced) = false; want true")
+	}
 }
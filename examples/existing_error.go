@@ -1,3 +1,5 @@
+//go:build ignore
+
 package main
 
 import (
@@ -19,5 +21,5 @@ func read() error {
 func readNonExistent() error {
 	_, err := os.ReadFile("/tmp/non_existent_file")
 	// Add stack trace to existing error, no matter if it's nil.
-	return tracerr.Wrap(err)
+	return tracerr.Wrap(err, "")
 }
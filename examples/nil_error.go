@@ -1,3 +1,5 @@
+//go:build ignore
+
 package main
 
 import (
@@ -15,5 +17,5 @@ func main() {
 }
 
 func nilError() error {
-	return tracerr.Wrap(nil)
+	return tracerr.Wrap(nil, "")
 }
@@ -1,3 +1,5 @@
+//go:build ignore
+
 package main
 
 import (
@@ -20,5 +22,5 @@ func read() error {
 
 func readNonExistent() error {
 	_, err := os.ReadFile("/tmp/non_existent_file")
-	return tracerr.Wrap(err)
+	return tracerr.Wrap(err, "")
 }
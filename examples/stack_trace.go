@@ -1,3 +1,5 @@
+//go:build ignore
+
 package main
 
 import (
@@ -21,5 +23,5 @@ func read() error {
 
 func readNonExistent() error {
 	_, err := os.ReadFile("/tmp/non_existent_file")
-	return tracerr.Wrap(err)
+	return tracerr.Wrap(err, "")
 }
@@ -0,0 +1,65 @@
+package tracerr
+
+import (
+	"time"
+)
+
+// Exit allows to replace os.Exit in tests.
+var Exit = &exit
+
+func ResetWrappers() {
+	wrappersMutex.Lock()
+	defer wrappersMutex.Unlock()
+	wrappers = nil
+}
+
+var RecordDepth = recordDepth
+
+func ResetDepthStats() {
+	depthStatsEnabled.Store(false)
+	for i := range depthCounts {
+		depthCounts[i].Store(0)
+	}
+}
+
+func WithStacks(err Error, stacks ...[]Frame) Error {
+	c := err.(*errorData).clone()
+	c.stacks = stacks
+	return c
+}
+
+func DisableCounting() {
+	countingEnabled.Store(false)
+}
+
+func DisableFrameCache() {
+	frameCacheEnabled.Store(false)
+}
+
+// Resolved checks if compact frames of err are resolved.
+func Resolved(err Error) bool {
+	e := err.(*errorData)
+	return e.lazy == nil || e.lazy.frames != nil
+}
+
+// FramesOfPCs resolves program counters the same way as capture.
+func FramesOfPCs(pcs []uintptr) []Frame {
+	var frames []Frame
+	iterator := newFrameIterator(pcs, 0)
+	for {
+		frame, more := iterator.next()
+		frames = append(frames, frame)
+		if !more {
+			return frames
+		}
+	}
+}
+
+// SetNow replaces current time source and returns a function to restore it.
+func SetNow(fn func() time.Time) func() {
+	original := now
+	now = fn
+	return func() {
+		now = original
+	}
+}
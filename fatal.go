@@ -0,0 +1,36 @@
+package tracerr
+
+import (
+	"fmt"
+	"os"
+)
+
+// FatalExitCode is an exit code used by Fatal.
+var FatalExitCode = 1
+
+// OnFatal is called by Fatal before a program exits,
+// e.g. to flush logs or metrics.
+var OnFatal func(err Error)
+
+// exit is replaced in tests.
+var exit = os.Exit
+
+// Fatal prints error message with stack trace and source fragments to stderr
+// and exits with FatalExitCode.
+// Stack trace is added if err is not of type Error.
+//
+// It does nothing if err is nil.
+func Fatal(err error) {
+	if err == nil {
+		return
+	}
+	e, ok := err.(Error)
+	if !ok {
+		e = trace(err, "", 2)
+	}
+	if OnFatal != nil {
+		OnFatal(e)
+	}
+	fmt.Fprint(os.Stderr, SprintSource(e)+LineSeparator)
+	exit(FatalExitCode)
+}
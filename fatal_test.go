@@ -0,0 +1,81 @@
+package tracerr_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestFatal(t *testing.T) {
+	exit := *tracerr.Exit
+	defer func() {
+		*tracerr.Exit = exit
+		tracerr.OnFatal = nil
+		tracerr.FatalExitCode = 1
+	}()
+	var codes []int
+	*tracerr.Exit = func(code int) {
+		codes = append(codes, code)
+	}
+	var calls []string
+	tracerr.OnFatal = func(err tracerr.Error) {
+		calls = append(calls, "OnFatal")
+		if len(err.StackTrace()) == 0 {
+			t.Errorf("err.StackTrace() = %#v; want non-empty", err.StackTrace())
+		}
+	}
+	tracerr.FatalExitCode = 3
+
+	output := captureStderr(func() {
+		tracerr.Fatal(nil)
+	})
+	if output != "" || len(codes) != 0 {
+		t.Errorf("tracerr.Fatal(nil): output, codes = %#v, %#v; want no output and exit", output, codes)
+	}
+
+	output = captureStderr(func() {
+		tracerr.Fatal(errors.New("fatal error"))
+	})
+	if len(calls) != 1 || len(codes) != 1 || codes[0] != 3 {
+		t.Errorf("calls, codes = %#v, %#v; want OnFatal and exit code 3", calls, codes)
+	}
+	rows := strings.Split(output, "\n")
+	if rows[0] != "fatal error" || !strings.HasSuffix(rows[2], "github.com/ztrue/tracerr_test.TestFatal.func5()") {
+		t.Errorf("output = %#v; want error with stack trace", output)
+	}
+	if !strings.Contains(output, "\t\ttracerr.Fatal(errors.New(\"fatal error\"))") {
+		t.Errorf("output = %#v; want source fragment", output)
+	}
+
+	separator := tracerr.LineSeparator
+	tracerr.LineSeparator = "\r\n"
+	defer func() {
+		tracerr.LineSeparator = separator
+	}()
+	output = captureStderr(func() {
+		tracerr.Fatal(errors.New("fatal error"))
+	})
+	if !strings.HasSuffix(output, "\r\n") || strings.HasSuffix(output, "\r\n\n") {
+		t.Errorf("output = %#v; want LineSeparator at the end", output)
+	}
+}
+
+func captureStderr(fn func()) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		panic(err.Error())
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	fn()
+	w.Close()
+	os.Stderr = stderr
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String()
+}
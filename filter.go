@@ -0,0 +1,57 @@
+package tracerr
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SkipPaths contains glob patterns of frame paths, which are dropped
+// from stack trace at capture time, such as generated or vendored code:
+//
+//	*/generated/*
+//	*_gen.go
+//
+// Pattern syntax is the same as in path.Match.
+// Pattern is matched against the whole path and each of its trailing parts,
+// e.g. "/src/app/api_gen.go", "src/app/api_gen.go", "app/api_gen.go"
+// and "api_gen.go", so it doesn't have to match from the root.
+var SkipPaths []string
+
+// IncludeRuntimeAsm makes frames of Go runtime and assembly code,
+// such as runtime.goexit of asm_amd64.s, always kept at capture time,
+// even if they are dropped by SkipPaths, Config.Filter
+// or CaptureOwnModuleOnly, which is useful for low level debugging.
+var IncludeRuntimeAsm = false
+
+// SkipPath adds glob pattern to SkipPaths.
+// Like other package settings, it should be called on initialization.
+func SkipPath(glob string) {
+	SkipPaths = append(SkipPaths, glob)
+}
+
+// skipPath checks if frame path matches any of SkipPaths.
+func skipPath(patterns []string, frame Frame) bool {
+	if len(patterns) == 0 || frame.Path == "" {
+		return false
+	}
+	p := filepath.ToSlash(frame.Path)
+	for {
+		for _, pattern := range patterns {
+			// Malformed pattern never matches.
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+		}
+		i := strings.Index(p, "/")
+		if i < 0 {
+			return false
+		}
+		p = p[i+1:]
+	}
+}
+
+// isRuntimeAsm checks if frame is a part of Go runtime or assembly code.
+func isRuntimeAsm(frame Frame) bool {
+	return IsRuntime(frame) || strings.HasSuffix(frame.Path, ".s")
+}
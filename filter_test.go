@@ -0,0 +1,85 @@
+package tracerr_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestSkipPaths(t *testing.T) {
+	defer func() {
+		tracerr.SkipPaths = nil
+	}()
+
+	tracerr.SkipPath("*/generated/*")
+	tracerr.SkipPath("*_gen.go")
+	tracerr.SkipPath("[")
+
+	// Frames of error_helper_test.go and filter_test.go remain.
+	frames := addFrameA("some error").(tracerr.Error).StackTrace()
+	if len(frames) < 4 || frames[0].Func != "github.com/ztrue/tracerr_test.addFrameC" {
+		t.Errorf("frames = %#v; want frames not matching SkipPaths", frames)
+	}
+
+	tracerr.SkipPath("error_helper_test.go")
+	frames = addFrameA("some error").(tracerr.Error).StackTrace()
+	if len(frames) == 0 || frames[0].Func != "github.com/ztrue/tracerr_test.TestSkipPaths" {
+		t.Errorf("frames[0] = %#v; want TestSkipPaths", frames)
+	}
+	for _, frame := range frames {
+		if strings.HasSuffix(frame.Path, "error_helper_test.go") {
+			t.Errorf("frame = %#v; want skipped", frame)
+		}
+	}
+
+	tracerr.SkipPaths = nil
+	tracerr.SkipPath("filter_*.go")
+	for _, frame := range tracerr.New("some error").StackTrace() {
+		if strings.HasSuffix(frame.Path, "filter_test.go") {
+			t.Errorf("frame = %#v; want skipped", frame)
+		}
+	}
+}
+
+func TestIncludeRuntimeAsm(t *testing.T) {
+	defer func() {
+		tracerr.SkipPaths = nil
+		tracerr.IncludeRuntimeAsm = false
+	}()
+	tracerr.SkipPath("asm_*.s")
+	tracerr.SkipPath("runtime/*")
+
+	hasRuntimeAsm := func(frames []tracerr.Frame) bool {
+		for _, frame := range frames {
+			if frame.Func == "runtime.goexit" && strings.HasSuffix(frame.Path, ".s") {
+				return true
+			}
+		}
+		return false
+	}
+	if frames := tracerr.New("some error").StackTrace(); hasRuntimeAsm(frames) {
+		t.Errorf("frames = %#v; want runtime.goexit skipped", frames)
+	}
+
+	tracerr.IncludeRuntimeAsm = true
+	if frames := tracerr.New("some error").StackTrace(); !hasRuntimeAsm(frames) {
+		t.Errorf("frames = %#v; want runtime.goexit kept", frames)
+	}
+	tracerr.SkipPaths = nil
+
+	config := &tracerr.Config{
+		Filter: func(frame tracerr.Frame) bool {
+			return !strings.HasPrefix(frame.Func, "runtime.")
+		},
+	}
+	ctx := tracerr.ContextWithConfig(context.Background(), config)
+	if frames := tracerr.NewCtx(ctx, "some error").StackTrace(); !hasRuntimeAsm(frames) {
+		t.Errorf("frames = %#v; want runtime.goexit kept", frames)
+	}
+	tracerr.IncludeRuntimeAsm = false
+	if frames := tracerr.NewCtx(ctx, "some error").StackTrace(); hasRuntimeAsm(frames) {
+		t.Errorf("frames = %#v; want runtime.goexit filtered", frames)
+	}
+}
@@ -0,0 +1,38 @@
+package tracerr
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+)
+
+// HashFrames returns a hash of frames as 16 hex digits,
+// which is the same for frames with the same functions, paths and lines.
+//
+// If ignoreLines is true, lines are not hashed, so stack traces
+// of the same code path are the same even if lines shift between builds.
+func HashFrames(frames []Frame, ignoreLines bool) string {
+	h := fnv.New64a()
+	for _, frame := range frames {
+		h.Write([]byte(frame.Func))
+		h.Write([]byte{0})
+		h.Write([]byte(frame.Path))
+		h.Write([]byte{0})
+		if !ignoreLines {
+			h.Write([]byte(strconv.Itoa(frame.Line)))
+		}
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// FingerprintError returns a hash of stack trace of err by HashFrames,
+// e.g. to group errors created at the same place.
+// It will be empty if err has no stack trace.
+func FingerprintError(err error, ignoreLines bool) string {
+	frames := StackTrace(err)
+	if len(frames) == 0 {
+		return ""
+	}
+	return HashFrames(frames, ignoreLines)
+}
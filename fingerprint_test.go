@@ -0,0 +1,48 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestHashFrames(t *testing.T) {
+	frames := []tracerr.Frame{
+		{Func: "main.read", Line: 12, Path: "/src/read.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	}
+	shifted := []tracerr.Frame{
+		{Func: "main.read", Line: 14, Path: "/src/read.go"},
+		{Func: "main.main", Line: 8, Path: "/src/main.go"},
+	}
+	other := []tracerr.Frame{
+		{Func: "main.write", Line: 12, Path: "/src/write.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	}
+
+	if tracerr.HashFrames(frames, false) == tracerr.HashFrames(shifted, false) {
+		t.Errorf("line-sensitive hashes of shifted frames are equal; want different")
+	}
+	if tracerr.HashFrames(frames, true) != tracerr.HashFrames(shifted, true) {
+		t.Errorf("line-insensitive hashes of shifted frames are different; want equal")
+	}
+	if tracerr.HashFrames(frames, true) == tracerr.HashFrames(other, true) {
+		t.Errorf("line-insensitive hashes of different frames are equal; want different")
+	}
+	if hash := tracerr.HashFrames(frames, false); len(hash) != 16 || hash != tracerr.HashFrames(frames, false) {
+		t.Errorf("tracerr.HashFrames() = %#v; want stable 16 hex digits", hash)
+	}
+
+	a := tracerr.CustomError(errors.New("first error"), frames)
+	b := tracerr.CustomError(errors.New("second error"), shifted)
+	if tracerr.FingerprintError(a, true) != tracerr.FingerprintError(b, true) {
+		t.Errorf("line-insensitive fingerprints are different; want equal")
+	}
+	if tracerr.FingerprintError(a, false) == tracerr.FingerprintError(b, false) {
+		t.Errorf("line-sensitive fingerprints are equal; want different")
+	}
+	if fingerprint := tracerr.FingerprintError(errors.New("regular error"), false); fingerprint != "" {
+		t.Errorf("tracerr.FingerprintError() = %#v; want empty", fingerprint)
+	}
+}
@@ -0,0 +1,40 @@
+package tracerr
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteFlamegraph writes stack traces of errs in collapsed stack format
+// of flamegraph.pl, one line per unique stack, the outermost function first:
+//
+//	main.main;main.read;main.readFile 3
+//
+// Lines are sorted, errors with no stack trace are skipped.
+func WriteFlamegraph(w io.Writer, errs []error) error {
+	counts := map[string]int{}
+	for _, err := range errs {
+		frames := StackTrace(err)
+		if len(frames) == 0 {
+			continue
+		}
+		names := make([]string, len(frames))
+		for i, frame := range frames {
+			names[len(frames)-1-i] = frame.Func
+		}
+		counts[strings.Join(names, ";")]++
+	}
+	stacks := make([]string, 0, len(counts))
+	for stack := range counts {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+	for _, stack := range stacks {
+		if _, err := fmt.Fprintf(w, "%s %d\n", stack, counts[stack]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
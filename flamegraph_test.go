@@ -0,0 +1,37 @@
+package tracerr_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestWriteFlamegraph(t *testing.T) {
+	read := []tracerr.Frame{
+		{Func: "main.readFile", Line: 42, Path: "/src/read.go"},
+		{Func: "main.read", Line: 12, Path: "/src/read.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	}
+	write := []tracerr.Frame{
+		{Func: "main.write", Line: 9, Path: "/src/write.go"},
+		{Func: "main.main", Line: 8, Path: "/src/main.go"},
+	}
+	errs := []error{
+		tracerr.CustomError(errors.New("read error"), read),
+		tracerr.CustomError(errors.New("write error"), write),
+		nil,
+		errors.New("regular error"),
+		tracerr.CustomError(errors.New("another read error"), read),
+	}
+	var buf bytes.Buffer
+	if err := tracerr.WriteFlamegraph(&buf, errs); err != nil {
+		t.Fatalf("tracerr.WriteFlamegraph() error: %s", err)
+	}
+	expected := "main.main;main.read;main.readFile 2\n" +
+		"main.main;main.write 1\n"
+	if buf.String() != expected {
+		t.Errorf("output = %#v; want %#v", buf.String(), expected)
+	}
+}
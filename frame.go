@@ -0,0 +1,190 @@
+package tracerr
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// FrameFromFunc creates a frame of function fn with provided line number.
+// It is useful to build stack trace for CustomError without hardcoded names.
+//
+// It will be a zero frame if fn is not a function.
+func FrameFromFunc(fn interface{}, line int) Frame {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return Frame{}
+	}
+	pc := v.Pointer()
+	f := runtime.FuncForPC(pc)
+	if f == nil {
+		return Frame{}
+	}
+	path, _ := f.FileLine(pc)
+	return Frame{
+		Func: f.Name(),
+		Line: line,
+		Path: path,
+	}
+}
+
+// Location returns frame location as "path:line",
+// which is accepted by "go to file" command of most editors.
+// Path is always full regardless of BaseNamesOnly.
+func (f Frame) Location() string {
+	return f.Path + ":" + strconv.Itoa(f.Line)
+}
+
+// Locations returns locations of all frames of err, see Frame.Location.
+// It will be empty if err is not of type Error.
+func Locations(err error) []string {
+	frames := StackTrace(err)
+	locations := make([]string, len(frames))
+	for i, frame := range frames {
+		locations[i] = frame.Location()
+	}
+	return locations
+}
+
+// Split returns package path and function name of frame,
+// e.g. "github.com/john/doe" and "(*Type).Method"
+// for "github.com/john/doe.(*Type).Method".
+// Type parameters of generic functions, such as "Map[...]",
+// and closures, such as "Func.func1", stay in function name.
+// Escaped dots in package path, e.g. "gopkg.in/yaml%2ev3", are unescaped.
+// Package is empty if function name has no package.
+func (f Frame) Split() (pkg, fn string) {
+	// The last slash and the first dot after it, outside of type parameters.
+	start := 0
+	dot := -1
+	depth := 0
+	for i := 0; i < len(f.Func); i++ {
+		switch f.Func[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '/':
+			if depth == 0 {
+				start = i + 1
+				dot = -1
+			}
+		case '.':
+			if depth == 0 && dot < 0 {
+				dot = i
+			}
+		}
+	}
+	if dot < 0 {
+		return "", f.Func[start:]
+	}
+	return strings.ReplaceAll(f.Func[:dot], "%2e", "."), f.Func[dot+1:]
+}
+
+// funcPackage returns package path of function fn,
+// e.g. "github.com/john/doe" for "github.com/john/doe.(*Type).Method".
+func funcPackage(fn string) string {
+	pkg, _ := Frame{Func: fn}.Split()
+	return pkg
+}
+
+// ZeroFrame checks if frame is a zero value, which has no data.
+func ZeroFrame(frame Frame) bool {
+	return frame == Frame{}
+}
+
+// IsRuntime checks if frame is a part of Go runtime,
+// such as runtime.goexit or runtime.gopanic.
+func IsRuntime(frame Frame) bool {
+	return strings.HasPrefix(frame.Func, "runtime.")
+}
+
+// InApp checks if frame is a part of the application code,
+// which is used by CaptureOwnModuleOnly, ToSentryFrames and Frame.InApp.
+// By default it is true for frames of the main module (detected via build info),
+// including functions of package main.
+// It can be replaced, e.g. to include other modules of the same project,
+// like other package settings it should be changed on initialization.
+var InApp = func(frame Frame) bool {
+	return inMainModule(frame.Func)
+}
+
+// InApp checks if frame is a part of the application code, see InApp variable.
+// Frames of the main module are in app if the variable is nil.
+func (f Frame) InApp() bool {
+	if InApp == nil {
+		return inMainModule(f.Func)
+	}
+	return InApp(f)
+}
+
+// IsTest checks if frame is a part of tests,
+// such as a function of a _test.go file or of testing package.
+func (f Frame) IsTest() bool {
+	return strings.HasSuffix(f.Path, "_test.go") || strings.HasPrefix(f.Func, "testing.")
+}
+
+// CountFrames returns number of frames in stack trace of err
+// for which predicate returns true, e.g. IsRuntime.
+// It will be 0 if err is not of type Error.
+func CountFrames(err error, predicate func(Frame) bool) int {
+	count := 0
+	for _, frame := range StackTrace(err) {
+		if predicate(frame) {
+			count++
+		}
+	}
+	return count
+}
+
+// FindFrame returns the first frame of stack trace of err
+// for which predicate returns true.
+// It will be false if err is not of type Error or there is no such frame.
+func FindFrame(err error, predicate func(Frame) bool) (Frame, bool) {
+	for _, frame := range StackTrace(err) {
+		if predicate(frame) {
+			return frame, true
+		}
+	}
+	return Frame{}, false
+}
+
+// CleanFunc returns function name without package,
+// where compiler generated names are replaced with readable ones:
+//
+//	pkg.Func.func1, pkg.Func.func1.2 -> Func[closure]
+//	pkg.(*Type).Method-fm            -> (*Type).Method[method value]
+//	pkg.glob..func3                  -> [anon#3]
+//
+// Func field is not modified.
+func (f Frame) CleanFunc() string {
+	_, name := f.Split()
+	if strings.HasSuffix(name, "-fm") {
+		return strings.TrimSuffix(name, "-fm") + "[method value]"
+	}
+	if strings.HasPrefix(name, "glob..func") {
+		return "[anon#" + strings.TrimPrefix(name, "glob..func") + "]"
+	}
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if i > 0 && isClosureName(part) {
+			return strings.Join(parts[:i], ".") + "[closure]"
+		}
+	}
+	return name
+}
+
+// isClosureName checks if part of function name looks like "func1".
+func isClosureName(part string) bool {
+	digits := strings.TrimPrefix(part, "func")
+	if len(digits) == len(part) || digits == "" {
+		return false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,334 @@
+package tracerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestFrameFromFunc(t *testing.T) {
+	frame := tracerr.FrameFromFunc(addFrameA, 9)
+	if frame.Func != "github.com/ztrue/tracerr_test.addFrameA" {
+		t.Errorf(
+			"frame.Func = %#v; want %#v",
+			frame.Func, "github.com/ztrue/tracerr_test.addFrameA",
+		)
+	}
+	if frame.Line != 9 {
+		t.Errorf("frame.Line = %#v; want %#v", frame.Line, 9)
+	}
+	if !strings.HasSuffix(frame.Path, "/error_helper_test.go") {
+		t.Errorf(
+			"frame.Path = %#v; want to has suffix %#v",
+			frame.Path, "/error_helper_test.go",
+		)
+	}
+
+	var nilFunc func()
+	for _, fn := range []interface{}{nil, 42, "addFrameA", nilFunc} {
+		if frame := tracerr.FrameFromFunc(fn, 1); frame != (tracerr.Frame{}) {
+			t.Errorf(
+				"tracerr.FrameFromFunc(%#v, 1) = %#v; want zero frame",
+				fn, frame,
+			)
+		}
+	}
+}
+
+func TestFrameInlined(t *testing.T) {
+	// addFrameB is small enough to be inlined into addFrameA.
+	err := addFrameA("some error").(tracerr.Error)
+	var inlined *tracerr.Frame
+	for i, frame := range err.StackTrace() {
+		if frame.Inlined {
+			inlined = &err.StackTrace()[i]
+			break
+		}
+	}
+	if inlined == nil {
+		t.Skip("no inlined frames, inlining is probably disabled with -gcflags=-l")
+	}
+	if !strings.HasPrefix(inlined.Func, "github.com/ztrue/tracerr_test.addFrame") {
+		t.Errorf(
+			"inlined.Func = %#v; want addFrame function",
+			inlined.Func,
+		)
+	}
+
+	defer func() {
+		tracerr.ShowInlined = false
+	}()
+	frame := tracerr.Frame{Func: "main.foo", Line: 42, Path: "foobar.go", Inlined: true}
+	if frame.String() != "foobar.go:42 main.foo()" {
+		t.Errorf("frame.String() = %#v; want %#v", frame.String(), "foobar.go:42 main.foo()")
+	}
+	tracerr.ShowInlined = true
+	if frame.String() != "foobar.go:42 main.foo() [inlined]" {
+		t.Errorf("frame.String() = %#v; want %#v", frame.String(), "foobar.go:42 main.foo() [inlined]")
+	}
+}
+
+func TestFrameCleanFunc(t *testing.T) {
+	cases := []struct {
+		Func     string
+		Expected string
+	}{
+		{Func: "main.main", Expected: "main"},
+		{Func: "github.com/john/doe.SomeFunc", Expected: "SomeFunc"},
+		{Func: "github.com/john/doe.SomeFunc.func1", Expected: "SomeFunc[closure]"},
+		{Func: "github.com/john/doe.SomeFunc.func1.2", Expected: "SomeFunc[closure]"},
+		{Func: "github.com/john/doe.(*Type).Method", Expected: "(*Type).Method"},
+		{Func: "github.com/john/doe.(*Type).Method.func2", Expected: "(*Type).Method[closure]"},
+		{Func: "github.com/john/doe.(*Type).Method-fm", Expected: "(*Type).Method[method value]"},
+		{Func: "github.com/john/doe.Type.Method-fm", Expected: "Type.Method[method value]"},
+		{Func: "github.com/john/doe.Map[...].func1", Expected: "Map[...][closure]"},
+		{Func: "github.com/john/doe.glob..func3", Expected: "[anon#3]"},
+		{Func: "github.com/john/doe.function", Expected: "function"},
+		{Func: "", Expected: ""},
+	}
+	for _, c := range cases {
+		frame := tracerr.Frame{Func: c.Func}
+		if frame.CleanFunc() != c.Expected {
+			t.Errorf(
+				"Frame{Func: %#v}.CleanFunc() = %#v; want %#v",
+				c.Func, frame.CleanFunc(), c.Expected,
+			)
+		}
+	}
+
+	closure := func() tracerr.Error {
+		return tracerr.New("some error")
+	}
+	frame := closure().StackTrace()[0]
+	if frame.CleanFunc() != "TestFrameCleanFunc[closure]" {
+		t.Errorf(
+			"frame.CleanFunc() = %#v; want %#v",
+			frame.CleanFunc(), "TestFrameCleanFunc[closure]",
+		)
+	}
+}
+
+func TestCountFrames(t *testing.T) {
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "main.read", Line: 12, Path: "/src/read.go"},
+		{Func: "runtime.gopanic", Line: 770, Path: "/go/src/runtime/panic.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+		{Func: "runtime.main", Line: 271, Path: "/go/src/runtime/proc.go"},
+		{Func: "runtime.goexit", Line: 1695, Path: "/go/src/runtime/asm_amd64.s"},
+	})
+	if count := tracerr.CountFrames(err, tracerr.IsRuntime); count != 3 {
+		t.Errorf("tracerr.CountFrames(IsRuntime) = %#v; want %#v", count, 3)
+	}
+	own := func(frame tracerr.Frame) bool {
+		return strings.HasPrefix(frame.Func, "main.")
+	}
+	if count := tracerr.CountFrames(err, own); count != 2 {
+		t.Errorf("tracerr.CountFrames(own) = %#v; want %#v", count, 2)
+	}
+	if count := tracerr.CountFrames(errors.New("some error"), tracerr.IsRuntime); count != 0 {
+		t.Errorf("tracerr.CountFrames(regular) = %#v; want %#v", count, 0)
+	}
+	// goexit frame of a test goroutine.
+	if count := tracerr.CountFrames(tracerr.New("some error"), tracerr.IsRuntime); count != 1 {
+		t.Errorf("tracerr.CountFrames(New) = %#v; want %#v", count, 1)
+	}
+}
+
+func TestFindFrame(t *testing.T) {
+	err := addFrameA("some error")
+	inFrameB := func(frame tracerr.Frame) bool {
+		return strings.Contains(frame.Func, "addFrameB")
+	}
+	frame, ok := tracerr.FindFrame(err, inFrameB)
+	if !ok || frame.Func != "github.com/ztrue/tracerr_test.addFrameB" || frame.Line != 13 {
+		t.Errorf("tracerr.FindFrame() = %#v, %#v; want addFrameB:13", frame, ok)
+	}
+	never := func(frame tracerr.Frame) bool {
+		return false
+	}
+	if frame, ok := tracerr.FindFrame(err, never); ok || frame != (tracerr.Frame{}) {
+		t.Errorf("tracerr.FindFrame(never) = %#v, %#v; want zero frame, false", frame, ok)
+	}
+	if frame, ok := tracerr.FindFrame(errors.New("some error"), inFrameB); ok || frame != (tracerr.Frame{}) {
+		t.Errorf("tracerr.FindFrame(regular) = %#v, %#v; want zero frame, false", frame, ok)
+	}
+}
+
+func TestFrameStringCgo(t *testing.T) {
+	cases := []struct {
+		Frame    tracerr.Frame
+		Expected string
+	}{
+		{Frame: tracerr.Frame{}, Expected: "?:0 unknown()"},
+		{Frame: tracerr.Frame{Func: "_cgo_topofstack"}, Expected: "?:0 _cgo_topofstack() [cgo]"},
+		{Frame: tracerr.Frame{Func: "_cgo_0b49d6ed4a0b_Cfunc_call", Line: 12, Path: "/src/_cgo_gotypes.go"}, Expected: "/src/_cgo_gotypes.go:12 _cgo_0b49d6ed4a0b_Cfunc_call() [cgo]"},
+		{Frame: tracerr.Frame{Func: "main.main", Line: 7, Path: "/src/main.go"}, Expected: "/src/main.go:7 main.main()"},
+	}
+	for _, c := range cases {
+		if c.Frame.String() != c.Expected {
+			t.Errorf("%#v.String() = %#v; want %#v", c.Frame, c.Frame.String(), c.Expected)
+		}
+	}
+}
+
+func TestFrameLocation(t *testing.T) {
+	defer func() {
+		tracerr.BaseNamesOnly = false
+	}()
+	tracerr.BaseNamesOnly = true
+
+	frame := tracerr.Frame{Func: "main.foo", Line: 42, Path: "/src/github.com/john/doe/foobar.go"}
+	if frame.Location() != "/src/github.com/john/doe/foobar.go:42" {
+		t.Errorf("frame.Location() = %#v; want %#v", frame.Location(), "/src/github.com/john/doe/foobar.go:42")
+	}
+
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		frame,
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	})
+	locations := tracerr.Locations(err)
+	expected := []string{"/src/github.com/john/doe/foobar.go:42", "/src/main.go:7"}
+	if strings.Join(locations, ",") != strings.Join(expected, ",") {
+		t.Errorf("tracerr.Locations() = %#v; want %#v", locations, expected)
+	}
+	if locations := tracerr.Locations(errors.New("some error")); len(locations) != 0 {
+		t.Errorf("tracerr.Locations(regular) = %#v; want empty", locations)
+	}
+}
+
+func TestCustomErrorTrailingZeroFrames(t *testing.T) {
+	frames := make([]tracerr.Frame, 4)
+	frames[0] = tracerr.Frame{Func: "main.read", Line: 12, Path: "/src/read.go"}
+	frames[2] = tracerr.Frame{Func: "main.main", Line: 7, Path: "/src/main.go"}
+	err := tracerr.CustomError(errors.New("some error"), frames)
+	if len(err.StackTrace()) != 3 {
+		t.Errorf("len(err.StackTrace()) = %#v; want %#v", len(err.StackTrace()), 3)
+	}
+	expected := "some error\n" +
+		"\t/src/read.go:12 main.read()\n" +
+		"\t?:0 unknown()\n" +
+		"\t/src/main.go:7 main.main()"
+	if err.Error() != expected {
+		t.Errorf("err.Error() = %#v; want %#v", err.Error(), expected)
+	}
+	if !tracerr.ZeroFrame(tracerr.Frame{}) || tracerr.ZeroFrame(frames[0]) {
+		t.Errorf("tracerr.ZeroFrame() is wrong")
+	}
+	if err := tracerr.CustomError(errors.New("some error"), make([]tracerr.Frame, 3)); len(err.StackTrace()) != 0 {
+		t.Errorf("err.StackTrace() = %#v; want empty", err.StackTrace())
+	}
+}
+
+func TestFrameSplit(t *testing.T) {
+	cases := []struct {
+		Func        string
+		ExpectedPkg string
+		ExpectedFn  string
+	}{
+		{Func: "main.main", ExpectedPkg: "main", ExpectedFn: "main"},
+		{Func: "github.com/john/doe.SomeFunc", ExpectedPkg: "github.com/john/doe", ExpectedFn: "SomeFunc"},
+		{Func: "github.com/john/doe.SomeFunc.func1", ExpectedPkg: "github.com/john/doe", ExpectedFn: "SomeFunc.func1"},
+		{Func: "github.com/john/doe.SomeFunc.func1.2", ExpectedPkg: "github.com/john/doe", ExpectedFn: "SomeFunc.func1.2"},
+		{Func: "github.com/john/doe.(*Type).Method", ExpectedPkg: "github.com/john/doe", ExpectedFn: "(*Type).Method"},
+		{Func: "github.com/john/doe.Type.Method", ExpectedPkg: "github.com/john/doe", ExpectedFn: "Type.Method"},
+		{Func: "github.com/john/doe.(*Type).Method-fm", ExpectedPkg: "github.com/john/doe", ExpectedFn: "(*Type).Method-fm"},
+		{Func: "github.com/john/doe.(*Type[...]).Method", ExpectedPkg: "github.com/john/doe", ExpectedFn: "(*Type[...]).Method"},
+		{Func: "github.com/john/doe.Map[...]", ExpectedPkg: "github.com/john/doe", ExpectedFn: "Map[...]"},
+		{Func: "github.com/john/doe.Map[...].func1", ExpectedPkg: "github.com/john/doe", ExpectedFn: "Map[...].func1"},
+		{
+			Func:        "github.com/john/doe.Map[github.com/jane/roe.Key,go.shape.int]",
+			ExpectedPkg: "github.com/john/doe",
+			ExpectedFn:  "Map[github.com/jane/roe.Key,go.shape.int]",
+		},
+		{Func: "github.com/john/doe.glob..func3", ExpectedPkg: "github.com/john/doe", ExpectedFn: "glob..func3"},
+		{Func: "gopkg.in/yaml%2ev3.Marshal", ExpectedPkg: "gopkg.in/yaml.v3", ExpectedFn: "Marshal"},
+		{Func: "net/http.(*conn).serve", ExpectedPkg: "net/http", ExpectedFn: "(*conn).serve"},
+		{Func: "runtime.goexit", ExpectedPkg: "runtime", ExpectedFn: "goexit"},
+		{Func: "github.com/john/doe_test.TestFoo", ExpectedPkg: "github.com/john/doe_test", ExpectedFn: "TestFoo"},
+		{Func: "_cgo_topofstack", ExpectedPkg: "", ExpectedFn: "_cgo_topofstack"},
+		{Func: "", ExpectedPkg: "", ExpectedFn: ""},
+	}
+	for _, c := range cases {
+		pkg, fn := tracerr.Frame{Func: c.Func}.Split()
+		if pkg != c.ExpectedPkg || fn != c.ExpectedFn {
+			t.Errorf(
+				"Frame{Func: %#v}.Split() = %#v, %#v; want %#v, %#v",
+				c.Func, pkg, fn, c.ExpectedPkg, c.ExpectedFn,
+			)
+		}
+	}
+}
+
+func TestFrameInApp(t *testing.T) {
+	inApp := tracerr.InApp
+	defer func() {
+		tracerr.InApp = inApp
+	}()
+
+	// Real frames, since ToSentryFrames detects module root by them.
+	frames := tracerr.New("some error").StackTrace()
+	own, std := frames[0], frames[1]
+	if std.Func != "testing.tRunner" {
+		t.Fatalf("frames[1] = %#v; want testing.tRunner", std)
+	}
+	if !own.InApp() || std.InApp() {
+		t.Errorf("own.InApp() = %t, std.InApp() = %t; want true, false", own.InApp(), std.InApp())
+	}
+
+	tracerr.InApp = func(frame tracerr.Frame) bool {
+		return strings.HasPrefix(frame.Func, "testing.")
+	}
+	if own.InApp() || !std.InApp() {
+		t.Errorf("own.InApp() = %t, std.InApp() = %t; want false, true", own.InApp(), std.InApp())
+	}
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{own, std})
+	sentryFrames := tracerr.ToSentryFrames(err)
+	if sentryFrames[0]["in_app"] != true || sentryFrames[1]["in_app"] != false {
+		t.Errorf("sentryFrames = %#v; want in_app by custom predicate", sentryFrames)
+	}
+
+	tracerr.InApp = nil
+	if !own.InApp() || std.InApp() {
+		t.Errorf("own.InApp() = %t, std.InApp() = %t; want true, false", own.InApp(), std.InApp())
+	}
+}
+
+func TestFrameIsTest(t *testing.T) {
+	cases := []struct {
+		Frame    tracerr.Frame
+		Expected bool
+	}{
+		{Frame: tracerr.Frame{Func: "main.read", Path: "/src/read.go"}, Expected: false},
+		{Frame: tracerr.Frame{Func: "main.TestRead", Path: "/src/read_test.go"}, Expected: true},
+		{Frame: tracerr.Frame{Func: "testing.tRunner", Path: "/go/src/testing/testing.go"}, Expected: true},
+		{Frame: tracerr.Frame{Func: "runtime.goexit", Path: "/go/src/runtime/asm_amd64.s"}, Expected: false},
+		{Frame: tracerr.Frame{Func: "main.testingHelper", Path: "/src/helper.go"}, Expected: false},
+	}
+
+	for i, c := range cases {
+		if isTest := c.Frame.IsTest(); isTest != c.Expected {
+			t.Errorf("cases[%#v]: frame.IsTest() = %#v; want %#v", i, isTest, c.Expected)
+		}
+	}
+
+	tracerr.DimTestFrames = true
+	defer func() {
+		tracerr.DimTestFrames = false
+	}()
+	frames := make([]tracerr.Frame, len(cases))
+	for i, c := range cases {
+		frames[i] = c.Frame
+	}
+	output := tracerr.SprintSourceColor(tracerr.CustomError(errors.New("some error"), frames), 0)
+	expected := "some error\n" +
+		"\x1b[1m/src/read.go:0 main.read()\x1b[0m\n" +
+		"\x1b[2m/src/read_test.go:0 main.TestRead()\x1b[0m\n" +
+		"\x1b[2m/go/src/testing/testing.go:0 testing.tRunner()\x1b[0m\n" +
+		"\x1b[1m/go/src/runtime/asm_amd64.s:0 runtime.goexit()\x1b[0m\n" +
+		"\x1b[1m/src/helper.go:0 main.testingHelper()\x1b[0m"
+	if output != expected {
+		t.Errorf("tracerr.SprintSourceColor() = %#v; want %#v", output, expected)
+	}
+}
@@ -0,0 +1,103 @@
+package tracerr
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	frameCacheEnabled atomic.Bool
+	frameCache        sync.Map // uintptr -> []Frame
+)
+
+// EnableFrameCache turns on caching of frames by program counter,
+// so resolving function, path and line of the same place is done once.
+// It speeds up capturing when errors are created at the same places
+// again and again, e.g. in loops, for cost of memory for each place.
+// It only helps when stack trace is captured, not rendered.
+func EnableFrameCache() {
+	frameCacheEnabled.Store(true)
+}
+
+// frameIterator resolves program counters to frames,
+// using frame cache if it is enabled.
+// If budget is positive, program counters over it are not resolved,
+// see SymbolizeBudget.
+type frameIterator struct {
+	callers *runtime.Frames
+	pcs     []uintptr
+	pending []Frame
+	budget  int
+	// resolved is a number of resolved program counters.
+	resolved int
+}
+
+func newFrameIterator(pcs []uintptr, budget int) *frameIterator {
+	if frameCacheEnabled.Load() || budget > 0 {
+		return &frameIterator{pcs: pcs, budget: budget}
+	}
+	return &frameIterator{callers: runtime.CallersFrames(pcs)}
+}
+
+// next returns the next frame and whether there are more frames.
+func (it *frameIterator) next() (Frame, bool) {
+	if len(it.pending) == 0 && it.callers == nil && len(it.pcs) > 0 {
+		pc := it.pcs[0]
+		if it.budget > 0 && it.resolved == it.budget {
+			it.pcs = it.pcs[1:]
+			return Frame{PC: pc}, len(it.pcs) > 0
+		}
+		it.resolved++
+		it.pending = resolvePC(pc)
+		if n := len(it.pending); n > 0 && it.pending[n-1].Func == "runtime.sigpanic" {
+			// Program counter after sigpanic is not a return address,
+			// which is handled by CallersFrames only for the whole stack.
+			it.callers = runtime.CallersFrames(it.pcs)
+			it.callers.Next()
+			it.pcs = nil
+		} else {
+			it.pcs = it.pcs[1:]
+		}
+	}
+	if len(it.pending) > 0 {
+		frame := it.pending[0]
+		it.pending = it.pending[1:]
+		return frame, len(it.pending) > 0 || len(it.pcs) > 0 || it.callers != nil
+	}
+	if it.callers == nil {
+		return Frame{}, false
+	}
+	f, more := it.callers.Next()
+	return Frame{
+		Func:    f.Function,
+		Line:    f.Line,
+		Path:    f.File,
+		Inlined: f.Func == nil,
+	}, more
+}
+
+// resolvePC returns frames of program counter, including inlined calls.
+func resolvePC(pc uintptr) []Frame {
+	if frames, ok := frameCache.Load(pc); ok {
+		return frames.([]Frame)
+	}
+	var frames []Frame
+	callers := runtime.CallersFrames([]uintptr{pc})
+	for {
+		f, more := callers.Next()
+		frames = append(frames, Frame{
+			Func:    f.Function,
+			Line:    f.Line,
+			Path:    f.File,
+			Inlined: f.Func == nil,
+		})
+		if !more {
+			break
+		}
+	}
+	if frameCacheEnabled.Load() {
+		frameCache.Store(pc, frames)
+	}
+	return frames
+}
@@ -0,0 +1,79 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestEnableFrameCache(t *testing.T) {
+	defer tracerr.DisableFrameCache()
+
+	var errs []error
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			tracerr.EnableFrameCache()
+		}
+		// Inlined frames are resolved from cache the same way.
+		errs = append(errs, addFrameA("some error"))
+	}
+	expected := tracerr.StackTrace(errs[0])
+	for i, err := range errs[1:] {
+		frames := tracerr.StackTrace(err)
+		if len(frames) != len(expected) {
+			t.Fatalf("errs[%d]: frames = %#v; want %#v", i+1, frames, expected)
+		}
+		for j := range expected {
+			if frames[j] != expected[j] {
+				t.Errorf("errs[%d]: frames[%d] = %#v; want %#v", i+1, j, frames[j], expected[j])
+			}
+		}
+	}
+}
+
+func TestEnableFrameCacheSigpanic(t *testing.T) {
+	defer tracerr.DisableFrameCache()
+
+	var stacks [][]tracerr.Frame
+	for i := 0; i < 2; i++ {
+		if i > 0 {
+			tracerr.EnableFrameCache()
+		}
+		stacks = append(stacks, tracerr.StackTrace(derefNil()))
+	}
+	expected, frames := stacks[0], stacks[1]
+	if len(frames) != len(expected) {
+		t.Fatalf("frames = %#v; want %#v", frames, expected)
+	}
+	for i := range expected {
+		if frames[i] != expected[i] {
+			t.Errorf("frames[%d] = %#v; want %#v", i, frames[i], expected[i])
+		}
+	}
+}
+
+func derefNil() (err error) {
+	defer tracerr.RecoverInto(&err)
+	var p *tracerr.Frame
+	_ = p.Line
+	return errors.New("unreachable")
+}
+
+func TestUnknownPC(t *testing.T) {
+	defer tracerr.DisableFrameCache()
+
+	for i := 0; i < 2; i++ {
+		if i > 0 {
+			tracerr.EnableFrameCache()
+		}
+		// Program counter which belongs to no function.
+		frames := tracerr.FramesOfPCs([]uintptr{1})
+		if len(frames) != 1 || frames[0].Func != "" || frames[0].Line != 0 {
+			t.Errorf("frames = %#v; want a single frame with no function", frames)
+		}
+		if s := frames[0].String(); s != "?:0 unknown()" {
+			t.Errorf("frames[0].String() = %#v; want %#v", s, "?:0 unknown()")
+		}
+	}
+}
@@ -1,3 +1,8 @@
 module github.com/ztrue/tracerr
 
 go 1.21.0
+
+require (
+	github.com/google/go-cmp v0.6.0
+	golang.org/x/sync v0.7.0
+)
@@ -0,0 +1,58 @@
+package tracerr
+
+import (
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+// SyntaxHighlight makes color source printers highlight Go syntax
+// of source fragments: keywords, strings and comments.
+// The traced line is still displayed in red.
+// Lines which are not valid Go on their own, e.g. a part of a raw string
+// or a multiline comment, as well as non-Go files are displayed as is.
+var SyntaxHighlight = false
+
+// highlightGo returns a line of Go source with highlighted syntax
+// or the line as is if it can not be scanned.
+func highlightGo(line string) string {
+	src := []byte(line)
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	failed := false
+	var s scanner.Scanner
+	s.Init(file, src, func(token.Position, string) {
+		failed = true
+	}, scanner.ScanComments)
+	builder := strings.Builder{}
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		var colorize func(string) string
+		switch {
+		case tok.IsKeyword():
+			colorize = blue
+		case tok == token.STRING || tok == token.CHAR:
+			colorize = green
+		case tok == token.COMMENT:
+			colorize = cyan
+		default:
+			continue
+		}
+		offset := file.Offset(pos)
+		if offset < last || offset+len(lit) > len(line) {
+			return line
+		}
+		builder.WriteString(line[last:offset])
+		builder.WriteString(colorize(lit))
+		last = offset + len(lit)
+	}
+	if failed {
+		return line
+	}
+	builder.WriteString(line[last:])
+	return builder.String()
+}
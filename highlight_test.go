@@ -0,0 +1,46 @@
+package tracerr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestSyntaxHighlight(t *testing.T) {
+	defer func() {
+		tracerr.SyntaxHighlight = false
+	}()
+	err := highlightedError()
+
+	output := tracerr.SprintSourceColor(err, 5)
+	if strings.Contains(output, "\x1b[34mvar\x1b[0m") {
+		t.Errorf("output = %#v; want no highlighting", output)
+	}
+
+	tracerr.SyntaxHighlight = true
+	output = tracerr.SprintSourceColor(err, 5)
+	expected := []string{
+		"\t\t\x1b[34mvar\x1b[0m message = \x1b[32m\"some error\"\x1b[0m \x1b[36m// Highlighted.\x1b[0m\n",
+		"\t\x1b[34mfunc\x1b[0m highlightedError() error {\n",
+		// The traced line is red only.
+		"\x1b[31m",
+		"\t\terr := tracerr.New(message)\x1b[0m\n",
+		// Part of a raw string is not valid Go on its own.
+		"\t\traw := `not highlighted\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("output = %#v; want to contain %#v", output, e)
+		}
+	}
+}
+
+func highlightedError() error {
+	var message = "some error" // Highlighted.
+	err := tracerr.New(message)
+	raw := `not highlighted
+	`
+	_ = raw
+	return err
+}
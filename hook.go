@@ -0,0 +1,62 @@
+package tracerr
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// OnTrace is called for every error with captured stack trace,
+// such as created by New, Errorf or Wrap.
+// It is not called for errors created by CustomError.
+//
+// It is called synchronously, so it should be fast,
+// and it must be set up before errors are created.
+var OnTrace func(err Error)
+
+// OnFlush is called by FlushHooks.
+// It should be set up along with OnTrace if OnTrace buffers errors
+// and handles them asynchronously, e.g. ships them to a remote service.
+//
+// It must block until all buffered errors are handled
+// or ctx is done, whichever happens first.
+var OnFlush func(ctx context.Context) error
+
+// FlushHooks waits until errors buffered by OnTrace are handled,
+// it should be called before a program exits.
+// It does nothing if OnFlush is not set up.
+func FlushHooks(ctx context.Context) error {
+	if OnFlush == nil {
+		return nil
+	}
+	return OnFlush(ctx)
+}
+
+// OnTraceSampled sets up OnTrace to call fn
+// only for a sampleRate fraction of traced errors, e.g. 0.01 for 1%.
+//
+// Sampling is best-effort and based on a counter rather than random numbers,
+// so every N-th error is passed to fn.
+func OnTraceSampled(sampleRate float64, fn func(err Error)) {
+	if sampleRate >= 1 {
+		OnTrace = fn
+		return
+	}
+	if sampleRate <= 0 || fn == nil {
+		OnTrace = nil
+		return
+	}
+	var counter uint64
+	OnTrace = func(err Error) {
+		n := atomic.AddUint64(&counter, 1)
+		if uint64(float64(n)*sampleRate) != uint64(float64(n-1)*sampleRate) {
+			fn(err)
+		}
+	}
+}
+
+func observe(e Error) {
+	keepRecent(e)
+	if OnTrace != nil {
+		OnTrace(e)
+	}
+}
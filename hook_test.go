@@ -0,0 +1,107 @@
+package tracerr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestOnTrace(t *testing.T) {
+	defer func() {
+		tracerr.OnTrace = nil
+	}()
+	var traced []tracerr.Error
+	tracerr.OnTrace = func(err tracerr.Error) {
+		traced = append(traced, err)
+	}
+	err1 := tracerr.New("error 1")
+	err2 := tracerr.Wrap(errors.New("error 2"), "")
+	tracerr.CustomError(errors.New("error 3"), nil)
+	err4 := tracerr.Errorf("error 4: %w", err1)
+	if len(traced) != 3 || traced[0] != err1 || traced[1] != err2 || traced[2] != err4 {
+		t.Errorf(
+			"traced = %#v; want %#v",
+			traced, []tracerr.Error{err1, err2, err4},
+		)
+	}
+}
+
+func TestOnTraceSampled(t *testing.T) {
+	defer func() {
+		tracerr.OnTrace = nil
+	}()
+	cases := []struct {
+		SampleRate    float64
+		ExpectedCalls int
+	}{
+		{SampleRate: -1, ExpectedCalls: 0},
+		{SampleRate: 0, ExpectedCalls: 0},
+		{SampleRate: 0.01, ExpectedCalls: 2},
+		{SampleRate: 0.25, ExpectedCalls: 50},
+		{SampleRate: 0.5, ExpectedCalls: 100},
+		{SampleRate: 1, ExpectedCalls: 200},
+		{SampleRate: 2, ExpectedCalls: 200},
+	}
+	for i, c := range cases {
+		calls := 0
+		tracerr.OnTraceSampled(c.SampleRate, func(err tracerr.Error) {
+			calls++
+		})
+		for j := 0; j < 200; j++ {
+			tracerr.New("some error")
+		}
+		if calls != c.ExpectedCalls {
+			t.Errorf(
+				"cases[%#v]: calls = %#v; want %#v",
+				i, calls, c.ExpectedCalls,
+			)
+		}
+	}
+}
+
+func TestFlushHooks(t *testing.T) {
+	defer func() {
+		tracerr.OnTrace = nil
+		tracerr.OnFlush = nil
+	}()
+	if err := tracerr.FlushHooks(context.Background()); err != nil {
+		t.Errorf("tracerr.FlushHooks() = %#v; want nil", err)
+	}
+
+	buffer := make(chan tracerr.Error, 10)
+	var shipped []tracerr.Error
+	done := make(chan struct{})
+	tracerr.OnTrace = func(err tracerr.Error) {
+		buffer <- err
+	}
+	tracerr.OnFlush = func(ctx context.Context) error {
+		close(buffer)
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	go func() {
+		for err := range buffer {
+			shipped = append(shipped, err)
+		}
+		close(done)
+	}()
+
+	for i := 0; i < 3; i++ {
+		tracerr.New("some error")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := tracerr.FlushHooks(ctx); err != nil {
+		t.Errorf("tracerr.FlushHooks() = %#v; want nil", err)
+	}
+	if len(shipped) != 3 {
+		t.Errorf("len(shipped) = %#v; want %#v", len(shipped), 3)
+	}
+}
@@ -0,0 +1,49 @@
+// Package httpx creates errors of tracerr from responses of HTTP clients.
+//
+// It is a separate package to keep tracerr free of net/http dependency.
+package httpx
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ztrue/tracerr"
+)
+
+// MaxBodySize limits number of bytes of response body read by FromResponse,
+// so huge responses never cause huge allocations.
+var MaxBodySize int64 = 1024
+
+func init() {
+	// Errors are created at the place where FromResponse is called.
+	tracerr.RegisterWrapper("github.com/ztrue/tracerr/httpx.FromResponse")
+}
+
+// FromResponse creates an error with stacktrace for a response,
+// e.g. for a response with non-2xx status code.
+// Error message contains status and up to MaxBodySize bytes of body,
+// status code is attached by tracerr.WithStatus
+// and annotations "status" and "url" are attached by tracerr.Annotate.
+//
+// Body is read partially and it is not closed, which is up to the caller.
+// It will be nil if resp is nil.
+func FromResponse(resp *http.Response, message string) tracerr.Error {
+	if resp == nil {
+		return nil
+	}
+	text := "unexpected status " + resp.Status
+	if resp.Body != nil && MaxBodySize > 0 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, MaxBodySize))
+		if body := strings.TrimSpace(string(b)); body != "" {
+			text += ": " + body
+		}
+	}
+	var err error = tracerr.Wrap(errors.New(text), message)
+	err = tracerr.Annotate(err, "status", resp.StatusCode)
+	if resp.Request != nil && resp.Request.URL != nil {
+		err = tracerr.Annotate(err, "url", resp.Request.URL.String())
+	}
+	return tracerr.WithStatus(err, resp.StatusCode)
+}
@@ -0,0 +1,68 @@
+package httpx_test
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+	"github.com/ztrue/tracerr/httpx"
+)
+
+func TestFromResponse(t *testing.T) {
+	u, _ := url.Parse("https://example.com/users/42")
+	resp := &http.Response{
+		Status:     "404 Not Found",
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(strings.NewReader("user not found\n")),
+		Request:    &http.Request{Method: http.MethodGet, URL: u},
+	}
+	err := httpx.FromResponse(resp, "failed to get user")
+	if err == nil {
+		t.Fatalf("httpx.FromResponse() = nil; want error")
+	}
+	expected := "failed to get user\nunexpected status 404 Not Found: user not found"
+	if text := tracerr.Sprint(tracerr.Quiet(err)); !strings.HasPrefix(text, expected+"\n") {
+		t.Errorf("text = %#v; want prefix %#v", text, expected)
+	}
+	if status, ok := tracerr.StatusOf(err); status != http.StatusNotFound || !ok {
+		t.Errorf("tracerr.StatusOf() = %d, %t; want %d, true", status, ok, http.StatusNotFound)
+	}
+	annotations := tracerr.Annotations(err)
+	if annotations["status"] != http.StatusNotFound || annotations["url"] != "https://example.com/users/42" {
+		t.Errorf("tracerr.Annotations() = %#v; want status and url", annotations)
+	}
+	if frame := err.StackTrace()[0]; frame.Func != "github.com/ztrue/tracerr/httpx_test.TestFromResponse" {
+		t.Errorf("err.StackTrace()[0] = %#v; want TestFromResponse", frame)
+	}
+
+	if err := httpx.FromResponse(nil, "failed"); err != nil {
+		t.Errorf("httpx.FromResponse(nil) = %#v; want nil", err)
+	}
+}
+
+func TestFromResponseMaxBodySize(t *testing.T) {
+	maxBodySize := httpx.MaxBodySize
+	defer func() {
+		httpx.MaxBodySize = maxBodySize
+	}()
+	httpx.MaxBodySize = 5
+	body := strings.NewReader(strings.Repeat("x", 100))
+	resp := &http.Response{
+		Status:     "500 Internal Server Error",
+		StatusCode: http.StatusInternalServerError,
+		Body:       io.NopCloser(body),
+	}
+	err := httpx.FromResponse(resp, "")
+	if text := tracerr.RootMessage(err); text != "unexpected status 500 Internal Server Error: xxxxx" {
+		t.Errorf("tracerr.RootMessage() = %#v; want body bounded to 5 bytes", text)
+	}
+	if body.Len() != 95 {
+		t.Errorf("unread body = %d bytes; want 95", body.Len())
+	}
+	if _, ok := tracerr.Annotations(err)["url"]; ok {
+		t.Errorf("tracerr.Annotations() = %#v; want no url", tracerr.Annotations(err))
+	}
+}
@@ -0,0 +1,130 @@
+package tracerr_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestHighlightInApp(t *testing.T) {
+	inApp := tracerr.InApp
+	defer func() {
+		tracerr.InApp = inApp
+		tracerr.HighlightInApp = false
+	}()
+	tracerr.InApp = func(frame tracerr.Frame) bool {
+		return strings.HasPrefix(frame.Func, "main.")
+	}
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "io.ReadAll", Line: 12, Path: "/go/src/io/io.go"},
+		{Func: "main.read", Line: 42, Path: "/src/read.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	})
+
+	expected := "some error\n" +
+		"/go/src/io/io.go:12 io.ReadAll()\n" +
+		"/src/read.go:42 main.read()\n" +
+		"/src/main.go:7 main.main()"
+	if output := tracerr.Sprint(err); output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+
+	tracerr.HighlightInApp = true
+	expected = "some error\n" +
+		"/go/src/io/io.go:12 io.ReadAll()\n" +
+		"> /src/read.go:42 main.read()\n" +
+		"/src/main.go:7 main.main()"
+	if output := tracerr.Sprint(err); output != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", output, expected)
+	}
+
+	format := tracerr.SourceUnavailableFormat
+	tracerr.SourceUnavailableFormat = ""
+	defer func() {
+		tracerr.SourceUnavailableFormat = format
+	}()
+	expected = "some error\n\n" +
+		"\x1b[1m/go/src/io/io.go:12 io.ReadAll()\x1b[0m\n\n" +
+		"\x1b[1m\x1b[33m/src/read.go:42 main.read()\x1b[0m\x1b[0m\n\n" +
+		"\x1b[1m/src/main.go:7 main.main()\x1b[0m\n"
+	if output := tracerr.SprintSourceColor(err); output != expected {
+		t.Errorf("tracerr.SprintSourceColor(err) = %#v; want %#v", output, expected)
+	}
+}
+
+// mainProgram prints frames of an error created in package main of a module.
+const mainProgram = `package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ztrue/tracerr"
+)
+
+func fail() tracerr.Error {
+	return tracerr.New("some error")
+}
+
+func main() {
+	err := fail()
+	for _, frame := range err.StackTrace() {
+		fmt.Println(frame.Func, frame.InApp())
+	}
+	b, _ := json.Marshal(err)
+	fmt.Println(string(b))
+}
+`
+
+func TestInAppMainPackage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a program")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not available")
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	goSum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.21\n\n" +
+			"require github.com/ztrue/tracerr v0.0.0\n\n" +
+			"replace github.com/ztrue/tracerr => " + root + "\n",
+		"go.sum":  string(goSum),
+		"main.go": mainProgram,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run error: %s\n%s", err, output)
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	expected := []string{"main.fail true", "main.main true", "runtime.main false"}
+	for i, line := range expected {
+		if i >= len(lines) || lines[i] != line {
+			t.Errorf("cases[%#v]: output = %#v; want %#v", i, lines, line)
+		}
+	}
+	if json := lines[len(lines)-1]; !strings.Contains(json, `"rel_path":"main.go"`) {
+		t.Errorf("json = %#v; want rel_path of main.go", json)
+	}
+}
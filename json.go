@@ -0,0 +1,172 @@
+package tracerr
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// jsonFrame is a frame with path relative to the main module root,
+// which is empty for frames outside of the main module.
+type jsonFrame struct {
+	Frame
+	RelPath string `json:"rel_path,omitempty"`
+}
+
+type jsonError struct {
+	Messages    []string               `json:"messages,omitempty"`
+	Error       string                 `json:"error"`
+	Frames      []jsonFrame            `json:"frames"`
+	Annotations map[string]interface{} `json:"annotations,omitempty"`
+	Status      int                    `json:"status,omitempty"`
+	Retries     int                    `json:"retries,omitempty"`
+	Code        string                 `json:"code,omitempty"`
+}
+
+// MarshalJSON returns error message, stack trace and attached data as JSON,
+// where annotations are sorted by key, so output is stable.
+// Status, retries and code are taken from the whole chain
+// (see StatusOf, RetriesOf and CodeOf) and omitted if not set.
+func (e *errorData) MarshalJSON() ([]byte, error) {
+	status, found := StatusOf(e)
+	if !found {
+		// StatusOf returns default status, which is not attached to error.
+		status = 0
+	}
+	retries, _ := RetriesOf(e)
+	code, _ := CodeOf(e)
+	return json.Marshal(jsonError{
+		Messages:    e.messages,
+		Error:       errText(e.err),
+		Frames:      jsonFrames(e.StackTrace()),
+		Annotations: e.annotations,
+		Status:      status,
+		Retries:     retries,
+		Code:        code,
+	})
+}
+
+// EncodeJSONL writes errs to w in JSON lines format, one JSON object per line,
+// which is the same as MarshalJSON output for errors created by tracerr.
+// Other errors are written with error message and stack trace only,
+// nil errors are skipped.
+func EncodeJSONL(w io.Writer, errs []error) error {
+	encoder := json.NewEncoder(w)
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		var v interface{} = err
+		if _, ok := err.(*errorData); !ok {
+			v = jsonError{Error: errText(err), Frames: jsonFrames(StackTrace(err))}
+		}
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonMessage is an error without stack trace for MarshalMessage.
+type jsonMessage struct {
+	Messages []string `json:"messages,omitempty"`
+	Error    string   `json:"error"`
+}
+
+// MarshalMessage returns additional messages and the original error message
+// of err as JSON without stack trace, which is cheap to pass across
+// process boundaries, see UnmarshalMessage.
+// It will be nil if err is nil.
+func MarshalMessage(err error) []byte {
+	if err == nil {
+		return nil
+	}
+	m := jsonMessage{Error: err.Error()}
+	if e, ok := err.(*errorData); ok {
+		m.Messages = e.messages
+		m.Error = errText(e.err)
+	}
+	// Marshaling of strings never fails.
+	b, _ := json.Marshal(m)
+	return b
+}
+
+// UnmarshalMessage creates an error from output of MarshalMessage
+// with the same messages and error message, but with empty stack trace.
+// Data which is not produced by MarshalMessage is used as error message as is.
+// It will be nil if data is empty.
+func UnmarshalMessage(data []byte) Error {
+	if len(data) == 0 {
+		return nil
+	}
+	var m jsonMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		m = jsonMessage{Error: string(data)}
+	}
+	return &errorData{
+		err:      errors.New(m.Error),
+		messages: m.Messages,
+	}
+}
+
+// jsonFrames converts frames to JSON representation, which is never null.
+func jsonFrames(frames []Frame) []jsonFrame {
+	converted := make([]jsonFrame, len(frames))
+	for i, frame := range frames {
+		converted[i] = jsonFrame{Frame: frame, RelPath: relPath(frame)}
+	}
+	return converted
+}
+
+var (
+	moduleRootMutex sync.RWMutex
+	moduleRoot      string
+)
+
+// relPath returns path of frame relative to the main module root
+// or empty string if frame is outside of the main module.
+// Module root is found by the first frame of the main module and cached.
+func relPath(frame Frame) string {
+	if frame.Path == "" || !inMainModule(frame.Func) || mainModulePath() == "" {
+		return ""
+	}
+	moduleRootMutex.RLock()
+	root := moduleRoot
+	moduleRootMutex.RUnlock()
+	if root == "" {
+		root = rootOf(frame)
+		if root == "" {
+			return ""
+		}
+		moduleRootMutex.Lock()
+		moduleRoot = root
+		moduleRootMutex.Unlock()
+	}
+	p := filepath.ToSlash(frame.Path)
+	if !strings.HasPrefix(p, root+"/") {
+		return ""
+	}
+	return p[len(root)+1:]
+}
+
+// rootOf returns module root directory of a frame of the main module,
+// which is a directory of frame path without package directory inside module.
+func rootOf(frame Frame) string {
+	pkg := strings.TrimSuffix(funcPackage(frame.Func), "_test")
+	if pkg == "main" {
+		pkg = mainPackagePath()
+	}
+	rel := strings.TrimPrefix(strings.TrimPrefix(pkg, mainModulePath()), "/")
+	dir := path.Dir(filepath.ToSlash(frame.Path))
+	if rel == "" {
+		return dir
+	}
+	if !strings.HasSuffix(dir, "/"+rel) {
+		return ""
+	}
+	return strings.TrimSuffix(dir, "/"+rel)
+}
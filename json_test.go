@@ -0,0 +1,204 @@
+package tracerr_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	frames := []tracerr.Frame{
+		{Func: "main.foo", Line: 42, Path: "/src/github.com/john/doe/foobar.go"},
+	}
+	cases := []struct {
+		Error    error
+		Expected string
+	}{
+		{
+			Error:    tracerr.CustomError(errors.New("some error"), nil),
+			Expected: `{"error":"some error","frames":[]}`,
+		},
+		{
+			Error: tracerr.CustomError(errors.New("some error"), frames),
+			Expected: `{"error":"some error","frames":[` +
+				`{"func":"main.foo","line":42,"path":"/src/github.com/john/doe/foobar.go"}]}`,
+		},
+		{
+			Error: tracerr.WithStatus(
+				tracerr.Annotate(tracerr.CustomError(errors.New("some error"), frames), "user", 42),
+				404,
+			),
+			Expected: `{"error":"some error","frames":[` +
+				`{"func":"main.foo","line":42,"path":"/src/github.com/john/doe/foobar.go"}],` +
+				`"annotations":{"user":42},"status":404}`,
+		},
+		{
+			Error: tracerr.CustomError(fmt.Errorf("wrapped: %w", tracerr.Quiet(tracerr.WithCode(
+				tracerr.WithRetries(tracerr.WithStatus(errors.New("some error"), 404), 3),
+				"not_found",
+			))), frames),
+			Expected: `{"error":"wrapped: some error","frames":[` +
+				`{"func":"main.foo","line":42,"path":"/src/github.com/john/doe/foobar.go"}],` +
+				`"status":404,"retries":3,"code":"not_found"}`,
+		},
+	}
+	for i, c := range cases {
+		b, err := json.Marshal(c.Error)
+		if err != nil {
+			t.Fatalf("json.Marshal(cases[%#v].Error) error: %s", i, err)
+		}
+		if string(b) != c.Expected {
+			t.Errorf(
+				"json.Marshal(cases[%#v].Error) = %s; want %s",
+				i, b, c.Expected,
+			)
+		}
+	}
+}
+
+func TestEncodeJSONL(t *testing.T) {
+	frames := []tracerr.Frame{
+		{Func: "main.foo", Line: 42, Path: "/src/github.com/john/doe/foobar.go"},
+	}
+	errs := []error{
+		tracerr.Wrap(tracerr.CustomError(errors.New("first error"), frames), "failed"),
+		nil,
+		errors.New("regular error"),
+		tracerr.WithStatus(tracerr.CustomError(errors.New("second error"), frames), 404),
+		tracerr.Wrap(fmt.Errorf("wrapped: %w", tracerr.New("third error")), "failed"),
+		fmt.Errorf("wrapped: %w", tracerr.New("fourth error")),
+	}
+	var buf bytes.Buffer
+	if err := tracerr.EncodeJSONL(&buf, errs); err != nil {
+		t.Fatalf("tracerr.EncodeJSONL() error: %s", err)
+	}
+
+	type line struct {
+		Messages []string        `json:"messages"`
+		Error    string          `json:"error"`
+		Frames   []tracerr.Frame `json:"frames"`
+		Status   int             `json:"status"`
+	}
+	var lines []line
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var l line
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			t.Fatalf("json.Unmarshal(%#v) error: %s", scanner.Text(), err)
+		}
+		lines = append(lines, l)
+	}
+	if len(lines) != 5 {
+		t.Fatalf("len(lines) = %#v; want %#v", len(lines), 5)
+	}
+	if len(lines[0].Messages) != 1 || lines[0].Messages[0] != "failed" ||
+		lines[0].Error != "first error" || len(lines[0].Frames) != 1 || lines[0].Frames[0] != frames[0] {
+		t.Errorf("lines[0] = %#v; want first error", lines[0])
+	}
+	if lines[1].Error != "regular error" || lines[1].Frames == nil || len(lines[1].Frames) != 0 {
+		t.Errorf("lines[1] = %#v; want regular error with no frames", lines[1])
+	}
+	if lines[2].Error != "second error" || lines[2].Status != 404 {
+		t.Errorf("lines[2] = %#v; want second error with status", lines[2])
+	}
+	if lines[3].Error != "wrapped: third error" || len(lines[3].Frames) == 0 {
+		t.Errorf("lines[3] = %#v; want third error without stack trace", lines[3])
+	}
+	if lines[4].Error != "wrapped: fourth error" {
+		t.Errorf("lines[4] = %#v; want fourth error without stack trace", lines[4])
+	}
+}
+
+func TestMarshalJSONRelPath(t *testing.T) {
+	err := addFrameA("some error")
+	b, jsonErr := json.Marshal(err)
+	if jsonErr != nil {
+		t.Fatalf("json.Marshal() error: %s", jsonErr)
+	}
+	var decoded struct {
+		Frames []struct {
+			Path    string `json:"path"`
+			RelPath string `json:"rel_path"`
+			Func    string `json:"func"`
+		} `json:"frames"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error: %s", err)
+	}
+	if len(decoded.Frames) < 5 {
+		t.Fatalf("decoded.Frames = %#v; want at least 5 frames", decoded.Frames)
+	}
+	frames := tracerr.StackTrace(err)
+	project := []string{"error_helper_test.go", "error_helper_test.go", "error_helper_test.go", "json_test.go"}
+	for i, relPath := range project {
+		if decoded.Frames[i].Path != frames[i].Path || decoded.Frames[i].RelPath != relPath {
+			t.Errorf(
+				"decoded.Frames[%d] = %#v; want path %#v and rel_path %#v",
+				i, decoded.Frames[i], frames[i].Path, relPath,
+			)
+		}
+	}
+	// testing.tRunner is outside of the main module.
+	if decoded.Frames[4].Func != "testing.tRunner" || decoded.Frames[4].RelPath != "" || decoded.Frames[4].Path == "" {
+		t.Errorf("decoded.Frames[4] = %#v; want testing.tRunner with path only", decoded.Frames[4])
+	}
+}
+
+func TestMarshalMessage(t *testing.T) {
+	traced := tracerr.Wrap(tracerr.Wrap(addFrameA("some error"), "failed to read"), "failed to start")
+	cases := []struct {
+		Error            error
+		ExpectedMessages []string
+		ExpectedText     string
+	}{
+		{
+			Error:            traced,
+			ExpectedMessages: []string{"failed to start", "failed to read"},
+			ExpectedText:     "failed to start\nfailed to read\nsome error",
+		},
+		{
+			Error:        tracerr.New("some error"),
+			ExpectedText: "some error",
+		},
+		{
+			Error:        errors.New("regular error"),
+			ExpectedText: "regular error",
+		},
+	}
+	for i, c := range cases {
+		err := tracerr.UnmarshalMessage(tracerr.MarshalMessage(c.Error))
+		if err == nil {
+			t.Fatalf("cases[%#v]: tracerr.UnmarshalMessage() = nil", i)
+		}
+		messages := tracerr.Messages(err)
+		if len(messages) != len(c.ExpectedMessages) {
+			t.Errorf("cases[%#v]: messages = %#v; want %#v", i, messages, c.ExpectedMessages)
+		}
+		for j := range messages {
+			if j < len(c.ExpectedMessages) && messages[j] != c.ExpectedMessages[j] {
+				t.Errorf("cases[%#v]: messages = %#v; want %#v", i, messages, c.ExpectedMessages)
+			}
+		}
+		if len(err.StackTrace()) != 0 {
+			t.Errorf("cases[%#v]: err.StackTrace() = %#v; want empty", i, err.StackTrace())
+		}
+		if text := tracerr.Sprint(err); text != c.ExpectedText {
+			t.Errorf("cases[%#v]: tracerr.Sprint(err) = %#v; want %#v", i, text, c.ExpectedText)
+		}
+	}
+
+	if b := tracerr.MarshalMessage(nil); b != nil {
+		t.Errorf("tracerr.MarshalMessage(nil) = %s; want nil", b)
+	}
+	if err := tracerr.UnmarshalMessage(nil); err != nil {
+		t.Errorf("tracerr.UnmarshalMessage(nil) = %#v; want nil", err)
+	}
+	if err := tracerr.UnmarshalMessage([]byte("plain text")); err == nil || tracerr.Sprint(err) != "plain text" {
+		t.Errorf("tracerr.UnmarshalMessage() = %#v; want plain text error", err)
+	}
+}
@@ -0,0 +1,63 @@
+package tracerr
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MarkdownURLFormat is a format of links to source code of frames
+// in output of SprintMarkdown, where "{path}" is replaced with a path
+// relative to the main module root and "{line}" with a line number:
+//
+//	https://github.com/john/doe/blob/main/{path}#L{line}
+//
+// Frames are not linked if it is empty or frames are outside of the main module.
+var MarkdownURLFormat = ""
+
+// markdownEscaper escapes characters of emphasis and code in Markdown.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`")
+
+// SprintMarkdown returns error output in Markdown, e.g. for bug reports,
+// where each line of error message is bold and stack trace is a code block.
+// If MarkdownURLFormat is set, stack trace is a list instead,
+// where frames of the main module are linked to source code.
+// Output is not limited by MaxRenderBytes,
+// since truncation would break code blocks and links.
+func SprintMarkdown(err error) string {
+	if err == nil {
+		return ""
+	}
+	var rows []string
+	for _, line := range strings.Split(ErrorPrefix+errText(err), LineSeparator) {
+		if strings.TrimSpace(line) != "" {
+			line = "**" + markdownEscaper.Replace(line) + "**"
+		}
+		rows = append(rows, line)
+	}
+	frames := StackTrace(err)
+	if len(frames) == 0 {
+		return strings.Join(rows, LineSeparator)
+	}
+	rows = append(rows, "")
+	if MarkdownURLFormat == "" {
+		format := FrameFormat
+		if format == nil {
+			format = Frame.String
+		}
+		rows = append(rows, "```")
+		for _, frame := range frames {
+			rows = append(rows, format(frame))
+		}
+		rows = append(rows, "```")
+		return strings.Join(rows, LineSeparator)
+	}
+	for _, frame := range frames {
+		location := "`" + frame.displayPath() + ":" + strconv.Itoa(frame.Line) + "`"
+		if rel := relPath(frame); rel != "" {
+			url := strings.NewReplacer("{path}", rel, "{line}", strconv.Itoa(frame.Line)).Replace(MarkdownURLFormat)
+			location = "[" + location + "](" + url + ")"
+		}
+		rows = append(rows, "- "+location+" `"+frame.displayFunc()+"`")
+	}
+	return strings.Join(rows, LineSeparator)
+}
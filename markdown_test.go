@@ -0,0 +1,70 @@
+package tracerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestSprintMarkdown(t *testing.T) {
+	frames := []tracerr.Frame{
+		{Func: "main.read_file", Line: 42, Path: "/src/read.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	}
+	cases := []struct {
+		Error    error
+		Expected string
+	}{
+		{
+			Error: tracerr.Wrap(tracerr.CustomError(errors.New("open *.json: not found"), frames), "failed to read"),
+			Expected: "**failed to read**\n" +
+				"**open \\*.json: not found**\n" +
+				"\n" +
+				"```\n" +
+				"/src/read.go:42 main.read_file()\n" +
+				"/src/main.go:7 main.main()\n" +
+				"```",
+		},
+		{
+			Error:    errors.New("regular error"),
+			Expected: "**regular error**",
+		},
+		{
+			Error:    nil,
+			Expected: "",
+		},
+	}
+	for i, c := range cases {
+		if output := tracerr.SprintMarkdown(c.Error); output != c.Expected {
+			t.Errorf("cases[%#v]: tracerr.SprintMarkdown() = %#v; want %#v", i, output, c.Expected)
+		}
+	}
+}
+
+func TestSprintMarkdownLinks(t *testing.T) {
+	defer func() {
+		tracerr.MarkdownURLFormat = ""
+	}()
+	tracerr.MarkdownURLFormat = "https://github.com/ztrue/tracerr/blob/master/{path}#L{line}"
+	err := addFrameA("some error")
+	lines := strings.Split(tracerr.SprintMarkdown(err), "\n")
+	frames := tracerr.StackTrace(err)
+	if len(lines) != len(frames)+2 {
+		t.Fatalf("lines = %#v; want message, empty line and %d frames", lines, len(frames))
+	}
+	if lines[0] != "**some error**" || lines[1] != "" {
+		t.Errorf("lines[:2] = %#v; want bold message and empty line", lines[:2])
+	}
+	expected := "- [`" + frames[0].Path + ":17`](https://github.com/ztrue/tracerr/blob/master/error_helper_test.go#L17) " +
+		"`github.com/ztrue/tracerr_test.addFrameC()`"
+	if lines[2] != expected {
+		t.Errorf("lines[2] = %#v; want %#v", lines[2], expected)
+	}
+	// Frames outside of the main module are not linked.
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, "- `") || !strings.HasSuffix(last, " `runtime.goexit()`") {
+		t.Errorf("last line = %#v; want runtime.goexit not linked", last)
+	}
+}
@@ -0,0 +1,77 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestCollapseRepeatedMessages(t *testing.T) {
+	defer func() {
+		tracerr.CollapseRepeatedMessages = false
+	}()
+	var err error = tracerr.CustomError(errors.New("some error"), nil)
+	for _, message := range []string{"operation failed", "operation failed", "failed to read", "operation failed", "operation failed", "operation failed"} {
+		err = tracerr.Wrap(err, message)
+	}
+	expectedMessages := []string{
+		"operation failed",
+		"operation failed",
+		"operation failed",
+		"failed to read",
+		"operation failed",
+		"operation failed",
+	}
+
+	tracerr.CollapseRepeatedMessages = true
+	expected := "operation failed (×3)\n" +
+		"failed to read\n" +
+		"operation failed (×2)\n" +
+		"some error"
+	if text := tracerr.Sprint(err); text != expected {
+		t.Errorf("tracerr.Sprint() = %#v; want %#v", text, expected)
+	}
+	if messages := tracerr.Messages(err); !reflect.DeepEqual(messages, expectedMessages) {
+		t.Errorf("tracerr.Messages() = %#v; want %#v", messages, expectedMessages)
+	}
+
+	tracerr.CollapseRepeatedMessages = false
+	expected = "operation failed\n" +
+		"operation failed\n" +
+		"operation failed\n" +
+		"failed to read\n" +
+		"operation failed\n" +
+		"operation failed\n" +
+		"some error"
+	if text := tracerr.Sprint(err); text != expected {
+		t.Errorf("tracerr.Sprint() = %#v; want %#v", text, expected)
+	}
+}
+
+func TestHasMessage(t *testing.T) {
+	err := tracerr.Wrap(errors.New("connection refused"), "failed to connect to db")
+	err = tracerr.Wrap(fmt.Errorf("load users: %w", err), "failed to load users")
+	err = tracerr.Wrap(err, "failed to start server")
+	cases := []struct {
+		Error    error
+		Substr   string
+		Expected bool
+	}{
+		{Error: err, Substr: "failed to connect to db", Expected: true},
+		{Error: err, Substr: "load users", Expected: true},
+		{Error: err, Substr: "start server", Expected: true},
+		{Error: err, Substr: "failed to stop", Expected: false},
+		// Error messages are not additional messages.
+		{Error: err, Substr: "connection refused", Expected: false},
+		{Error: errors.New("failed to start"), Substr: "failed", Expected: false},
+		{Error: nil, Substr: "", Expected: false},
+	}
+	for i, c := range cases {
+		if ok := tracerr.HasMessage(c.Error, c.Substr); ok != c.Expected {
+			t.Errorf("cases[%#v]: tracerr.HasMessage(err, %#v) = %t; want %t", i, c.Substr, ok, c.Expected)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package tracerr_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestPanicOnNilWrap(t *testing.T) {
+	if err := tracerr.Wrap(nil, "message"); err != nil {
+		t.Errorf("tracerr.Wrap(nil) = %#v; want nil", err)
+	}
+
+	tracerr.PanicOnNilWrap = true
+	defer func() {
+		tracerr.PanicOnNilWrap = false
+	}()
+	cases := []struct {
+		Name string
+		Wrap func()
+	}{
+		{Name: "Wrap", Wrap: func() { tracerr.Wrap(nil, "message") }},
+		{Name: "Wrapf", Wrap: func() { tracerr.Wrapf(nil, "message %d", 42) }},
+		{Name: "WrapCtx", Wrap: func() { tracerr.WrapCtx(context.Background(), nil, "message") }},
+	}
+	for _, c := range cases {
+		r := recoverValue(c.Wrap)
+		message, ok := r.(string)
+		if !ok {
+			t.Errorf("%s: recovered = %#v; want string", c.Name, r)
+			continue
+		}
+		prefix := fmt.Sprintf("tracerr: %s called with nil error at ", c.Name)
+		if !strings.HasPrefix(message, prefix) {
+			t.Errorf("%s: message = %#v; want prefix %#v", c.Name, message, prefix)
+		}
+		if !strings.Contains(message, "nilwrap_test.go:") ||
+			!strings.Contains(message, "TestPanicOnNilWrap.func") {
+			t.Errorf("%s: message = %#v; want location of the caller", c.Name, message)
+		}
+	}
+	if r := recoverValue(func() { tracerr.Wrap(fmt.Errorf("some error"), "message") }); r != nil {
+		t.Errorf("tracerr.Wrap(err) panicked with %#v", r)
+	}
+}
+
+func recoverValue(fn func()) (r interface{}) {
+	defer func() {
+		r = recover()
+	}()
+	fn()
+	return nil
+}
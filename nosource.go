@@ -0,0 +1,21 @@
+package tracerr
+
+// NoSource returns a copy of err, which is printed without source fragments
+// by PrintSource and other source printers, the same way as by Print,
+// e.g. for errors of generated code, which source is not available.
+// The original error is not modified.
+//
+// Stack trace is added if err is not of type Error
+// and it will be nil if err is nil.
+func NoSource(err error) Error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(*errorData)
+	if !ok {
+		e = trace(err, "", 2).(*errorData)
+	}
+	c := e.clone()
+	c.noSource = true
+	return c
+}
@@ -0,0 +1,36 @@
+package tracerr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestNoSource(t *testing.T) {
+	err := addFrameA("some error")
+	if output := tracerr.SprintSource(err); !strings.Contains(output, "func addFrameC(message string) error {") {
+		t.Fatalf("tracerr.SprintSource() = %#v; want source fragments", output)
+	}
+	noSource := tracerr.NoSource(err)
+	for _, output := range []string{
+		tracerr.SprintSource(noSource),
+		tracerr.SprintSource(noSource, 5, 2),
+	} {
+		if output != tracerr.Sprint(err) {
+			t.Errorf("output = %#v; want %#v", output, tracerr.Sprint(err))
+		}
+	}
+	if output := tracerr.SprintSourceColor(noSource); strings.Contains(output, "func addFrameC(message string) error {") {
+		t.Errorf("tracerr.SprintSourceColor() = %#v; want no source fragments", output)
+	}
+	if output := tracerr.SprintSource(tracerr.Wrap(noSource, "")); output != tracerr.Sprint(err) {
+		t.Errorf("tracerr.SprintSource(wrapped) = %#v; want %#v", output, tracerr.Sprint(err))
+	}
+	if output := tracerr.SprintSource(err); !strings.Contains(output, "func addFrameC(message string) error {") {
+		t.Errorf("tracerr.SprintSource() = %#v; want original error not modified", output)
+	}
+	if err := tracerr.NoSource(nil); err != nil {
+		t.Errorf("tracerr.NoSource(nil) = %#v; want nil", err)
+	}
+}
@@ -0,0 +1,88 @@
+package tracerr
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// FromPanicLog creates an error from text output of a Go panic,
+// such as a log of a crashed process:
+//
+//	panic: assignment to entry in nil map
+//
+//	goroutine 1 [running]:
+//	main.(*T).Run(...)
+//		/home/john/app/main.go:7
+//	main.main()
+//		/home/john/app/main.go:12 +0x29
+//
+// Error message is the panic message and stack trace is the stack
+// of the first goroutine, including "created by" frame if any,
+// so it can be displayed with source fragments of the current checkout.
+func FromPanicLog(text string) (Error, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	i := 0
+	for i < len(lines) && !strings.HasPrefix(lines[i], "panic: ") {
+		i++
+	}
+	if i == len(lines) {
+		return nil, errors.New("tracerr: panic header not found")
+	}
+	message := strings.TrimSuffix(strings.TrimPrefix(lines[i], "panic: "), " [recovered]")
+	// Rest of a multiline panic message.
+	for i++; i < len(lines) && lines[i] != "" && !isGoroutineHeader(lines[i]); i++ {
+		message += "\n" + lines[i]
+	}
+	for i < len(lines) && !isGoroutineHeader(lines[i]) {
+		i++
+	}
+	if i == len(lines) {
+		return nil, errors.New("tracerr: goroutine header not found")
+	}
+	var frames []Frame
+	for i++; i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t"); i += 2 {
+		frames = append(frames, panicLogFrame(lines[i], lines[i+1]))
+	}
+	return CustomError(errors.New(message), frames), nil
+}
+
+// panicLogFrame returns frame from a pair of lines of panic output,
+// such as "main.main()" and "\t/home/john/app/main.go:12 +0x29".
+func panicLogFrame(funcLine, locationLine string) Frame {
+	frame := Frame{Func: panicLogFunc(funcLine)}
+	location := strings.TrimPrefix(locationLine, "\t")
+	if i := strings.LastIndex(location, " +0x"); i >= 0 {
+		location = location[:i]
+	}
+	if i := strings.LastIndex(location, ":"); i >= 0 {
+		frame.Path = location[:i]
+		frame.Line, _ = strconv.Atoi(location[i+1:])
+	} else {
+		frame.Path = location
+	}
+	return frame
+}
+
+// isGoroutineHeader checks if line looks like "goroutine 1 [running]:".
+func isGoroutineHeader(line string) bool {
+	return strings.HasPrefix(line, "goroutine ") && strings.HasSuffix(line, ":")
+}
+
+// panicLogFunc returns function name from a line of panic output,
+// such as "main.(*T).Run(0x1, ...)" or "created by main.main in goroutine 1".
+func panicLogFunc(line string) string {
+	if strings.HasPrefix(line, "created by ") {
+		line = strings.TrimPrefix(line, "created by ")
+		if i := strings.Index(line, " in goroutine "); i >= 0 {
+			line = line[:i]
+		}
+		return line
+	}
+	if strings.HasSuffix(line, ")") {
+		if i := strings.LastIndex(line, "("); i > 0 {
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,65 @@
+package tracerr_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+const panicLog = `panic: assignment to entry in nil map
+
+goroutine 1 [running]:
+main.(*T).Run(...)
+	/home/john/app/main.go:7
+main.main()
+	/home/john/app/main.go:12 +0x29
+exit status 2
+`
+
+const goroutinePanicLog = "panic: first line [recovered]\r\n" +
+	"\tsecond line\r\n" +
+	"\r\n" +
+	"goroutine 7 [running]:\r\n" +
+	"main.worker(0xc000012345, 0x2a)\r\n" +
+	"\t/home/john/app/worker.go:21 +0x65\r\n" +
+	"created by main.main in goroutine 1\r\n" +
+	"\t/home/john/app/main.go:9 +0x3e\r\n"
+
+func TestFromPanicLog(t *testing.T) {
+	err, parseErr := tracerr.FromPanicLog(panicLog)
+	if parseErr != nil {
+		t.Fatalf("tracerr.FromPanicLog() error: %s", parseErr)
+	}
+	if tracerr.Unwrap(err).Error() != "assignment to entry in nil map" {
+		t.Errorf("message = %#v; want %#v", tracerr.Unwrap(err).Error(), "assignment to entry in nil map")
+	}
+	expected := []tracerr.Frame{
+		{Func: "main.(*T).Run", Line: 7, Path: "/home/john/app/main.go"},
+		{Func: "main.main", Line: 12, Path: "/home/john/app/main.go"},
+	}
+	if !reflect.DeepEqual(err.StackTrace(), expected) {
+		t.Errorf("err.StackTrace() = %#v; want %#v", err.StackTrace(), expected)
+	}
+
+	err, parseErr = tracerr.FromPanicLog(goroutinePanicLog)
+	if parseErr != nil {
+		t.Fatalf("tracerr.FromPanicLog() error: %s", parseErr)
+	}
+	if tracerr.Unwrap(err).Error() != "first line\n\tsecond line" {
+		t.Errorf("message = %#v; want %#v", tracerr.Unwrap(err).Error(), "first line\n\tsecond line")
+	}
+	expected = []tracerr.Frame{
+		{Func: "main.worker", Line: 21, Path: "/home/john/app/worker.go"},
+		{Func: "main.main", Line: 9, Path: "/home/john/app/main.go"},
+	}
+	if !reflect.DeepEqual(err.StackTrace(), expected) {
+		t.Errorf("err.StackTrace() = %#v; want %#v", err.StackTrace(), expected)
+	}
+
+	for _, text := range []string{"", "some output", "panic: no goroutine"} {
+		if err, parseErr := tracerr.FromPanicLog(text); err != nil || parseErr == nil {
+			t.Errorf("tracerr.FromPanicLog(%#v) = %#v, %#v; want nil, error", text, err, parseErr)
+		}
+	}
+}
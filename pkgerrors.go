@@ -0,0 +1,82 @@
+package tracerr
+
+import (
+	"reflect"
+	"runtime"
+)
+
+// FromPkgErrors converts an error with stack trace of github.com/pkg/errors
+// into Error with the same stack trace, so no extra stack trace is captured.
+//
+// Any error with StackTrace() method returning a slice of program counters
+// is supported, such as errors.StackTrace of github.com/pkg/errors.
+// If there are several such errors in the chain,
+// the deepest one is used, since it is the closest to the origin.
+//
+// Stack trace is captured if there is no such error in the chain,
+// err is returned as is if it is already of type Error
+// and it will be nil if err is nil.
+func FromPkgErrors(err error) Error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(Error); ok {
+		return e
+	}
+	var pcs []uintptr
+	walk(err, func(current error) bool {
+		if stack, ok := pkgErrorsStack(current); ok {
+			pcs = stack
+		}
+		return true
+	})
+	if pcs == nil {
+		return trace(err, "", 2)
+	}
+	return CustomError(err, framesFromPCs(pcs))
+}
+
+// pkgErrorsStack returns program counters if err has StackTrace() method
+// which returns a slice of uintptr-based values.
+func pkgErrorsStack(err error) ([]uintptr, bool) {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() {
+		return nil, false
+	}
+	methodType := method.Type()
+	if methodType.NumIn() != 0 || methodType.NumOut() != 1 {
+		return nil, false
+	}
+	out := methodType.Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil, false
+	}
+	stack := method.Call(nil)[0]
+	pcs := make([]uintptr, stack.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(stack.Index(i).Uint())
+	}
+	return pcs, true
+}
+
+// framesFromPCs converts program counters returned by runtime.Callers into frames.
+func framesFromPCs(pcs []uintptr) []Frame {
+	frames := make([]Frame, 0, len(pcs))
+	if len(pcs) == 0 {
+		return frames
+	}
+	callersFrames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := callersFrames.Next()
+		frames = append(frames, Frame{
+			Func:    frame.Function,
+			Line:    frame.Line,
+			Path:    frame.File,
+			Inlined: frame.Func == nil,
+		})
+		if !more {
+			break
+		}
+	}
+	return frames
+}
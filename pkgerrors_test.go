@@ -0,0 +1,86 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+// pkgFrame and pkgStackTrace mimic types of github.com/pkg/errors.
+type pkgFrame uintptr
+
+type pkgStackTrace []pkgFrame
+
+type pkgError struct {
+	message string
+	stack   pkgStackTrace
+}
+
+func (e *pkgError) Error() string {
+	return e.message
+}
+
+func (e *pkgError) StackTrace() pkgStackTrace {
+	return e.stack
+}
+
+func newPkgError(message string) error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	stack := make(pkgStackTrace, n)
+	for i, pc := range pcs[:n] {
+		stack[i] = pkgFrame(pc)
+	}
+	return &pkgError{message: message, stack: stack}
+}
+
+func TestFromPkgErrors(t *testing.T) {
+	if tracerr.FromPkgErrors(nil) != nil {
+		t.Errorf("tracerr.FromPkgErrors(nil) = non-nil; want nil")
+	}
+
+	traced := tracerr.New("some error")
+	if tracerr.FromPkgErrors(traced) != traced {
+		t.Errorf("tracerr.FromPkgErrors(traced) != traced")
+	}
+
+	pkgErr := fmt.Errorf("wrapped: %w", newPkgError("pkg error"))
+	err := tracerr.FromPkgErrors(pkgErr)
+	if err.Unwrap() != pkgErr {
+		t.Errorf(
+			"tracerr.FromPkgErrors(pkgErr).Unwrap() = %#v; want %#v",
+			err.Unwrap(), pkgErr,
+		)
+	}
+	frames := err.StackTrace()
+	if len(frames) == 0 || frames[0].Func != "github.com/ztrue/tracerr_test.TestFromPkgErrors" {
+		t.Fatalf(
+			"tracerr.FromPkgErrors(pkgErr).StackTrace() = %#v; want to start with TestFromPkgErrors",
+			frames,
+		)
+	}
+	if frames[0].Line != 50 {
+		t.Errorf(
+			"tracerr.FromPkgErrors(pkgErr).StackTrace()[0].Line = %#v; want %#v",
+			frames[0].Line, 50,
+		)
+	}
+
+	regular := errors.New("regular error")
+	err = tracerr.FromPkgErrors(regular)
+	if err.Unwrap() != regular || len(err.StackTrace()) == 0 {
+		t.Errorf(
+			"tracerr.FromPkgErrors(regular) = %#v; want traced regular error",
+			err,
+		)
+	}
+	if err.StackTrace()[0].Func != "github.com/ztrue/tracerr_test.TestFromPkgErrors" {
+		t.Errorf(
+			"tracerr.FromPkgErrors(regular).StackTrace()[0].Func = %#v; want %#v",
+			err.StackTrace()[0].Func, "github.com/ztrue/tracerr_test.TestFromPkgErrors",
+		)
+	}
+}
@@ -0,0 +1,48 @@
+package tracerr
+
+import (
+	"strings"
+)
+
+// WrapTrim adds stacktrace to existing error the same way as Wrap,
+// but if the original error message starts with prefix, e.g. "pq: ",
+// it is displayed without prefix.
+// The original error is kept in the chain, so it still matches
+// by errors.Is and errors.As, but Unwrap returns an error
+// with trimmed message, which unwraps to the original one.
+func WrapTrim(err error, prefix, message string) Error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(*errorData)
+	if !ok {
+		return trace(trimPrefix(err, prefix), message, 2)
+	}
+	c := e.clone()
+	c.err = trimPrefix(e.err, prefix)
+	return c.withMessage(message)
+}
+
+// trimPrefix returns err with message without prefix
+// or err as is if its message has no prefix.
+func trimPrefix(err error, prefix string) error {
+	text := errText(err)
+	if prefix == "" || !strings.HasPrefix(text, prefix) {
+		return err
+	}
+	return trimmedError{err: err, text: strings.TrimPrefix(text, prefix)}
+}
+
+// trimmedError displays text instead of message of err.
+type trimmedError struct {
+	err  error
+	text string
+}
+
+func (e trimmedError) Error() string {
+	return e.text
+}
+
+func (e trimmedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,69 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+type driverError struct {
+	Code string
+}
+
+func (e *driverError) Error() string {
+	return "pq: duplicate key value violates unique constraint"
+}
+
+func TestWrapTrim(t *testing.T) {
+	original := &driverError{Code: "23505"}
+	cases := []struct {
+		Error        error
+		Prefix       string
+		Message      string
+		ExpectedText string
+	}{
+		{
+			Error:        original,
+			Prefix:       "pq: ",
+			Message:      "failed to create user",
+			ExpectedText: "failed to create user\nduplicate key value violates unique constraint",
+		},
+		{
+			Error:        tracerr.Wrap(original, "failed to insert"),
+			Prefix:       "pq: ",
+			Message:      "failed to create user",
+			ExpectedText: "failed to create user\nfailed to insert\nduplicate key value violates unique constraint",
+		},
+		{
+			Error:        tracerr.Wrap(original, ""),
+			Prefix:       "pq: ",
+			ExpectedText: "duplicate key value violates unique constraint",
+		},
+		{
+			Error:        original,
+			Prefix:       "mysql: ",
+			ExpectedText: "pq: duplicate key value violates unique constraint",
+		},
+	}
+	for i, c := range cases {
+		err := tracerr.WrapTrim(c.Error, c.Prefix, c.Message)
+		if text := fmt.Sprintf("%.0v", err); text != c.ExpectedText {
+			t.Errorf("cases[%#v]: text = %#v; want %#v", i, text, c.ExpectedText)
+		}
+		if !errors.Is(err, original) {
+			t.Errorf("cases[%#v]: errors.Is(err, original) = false; want true", i)
+		}
+		var target *driverError
+		if !errors.As(err, &target) || target.Code != "23505" {
+			t.Errorf("cases[%#v]: errors.As() = %#v; want original", i, target)
+		}
+		if len(err.StackTrace()) == 0 {
+			t.Errorf("cases[%#v]: err.StackTrace() is empty; want stack trace", i)
+		}
+	}
+	if err := tracerr.WrapTrim(nil, "pq: ", "failed"); err != nil {
+		t.Errorf("tracerr.WrapTrim(nil) = %#v; want nil", err)
+	}
+}
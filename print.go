@@ -2,10 +2,12 @@ package tracerr
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 // DefaultLinesAfter is number of source lines after traced line to display.
@@ -14,13 +16,49 @@ var DefaultLinesAfter = 2
 // DefaultLinesBefore is number of source lines before traced line to display.
 var DefaultLinesBefore = 3
 
+// SourceUnavailableFormat is a format of a line displayed
+// instead of source fragment when source file is not available.
+// It receives a file path as the only argument.
+// Set it to empty string to skip this line.
+var SourceUnavailableFormat = "// source unavailable: %s"
+
+// SourceReadTimeout limits time of reading a source file,
+// so slow or hung file system never blocks printing an error.
+// Frame is displayed without source fragment on timeout,
+// and the file is not read again until SetSourceBundle is called.
+// Set it to zero for no limit.
+var SourceReadTimeout = time.Second
+
+// FrameFormat formats frames in output of Print and Sprint functions,
+// Frame.String is used if it is nil.
+var FrameFormat func(Frame) string
+
+// HighlightInApp marks the first frame which is a part of the application code
+// (see InApp) in output of Print and PrintSource functions with ">" prefix,
+// or in yellow in output of PrintSourceColor.
+var HighlightInApp = false
+
+// DimTestFrames makes frames of tests (see Frame.IsTest) dim
+// in output of PrintSourceColor, so application frames stand out
+// in errors captured during tests.
+var DimTestFrames = false
+
+// Verbose makes PrintAuto and SprintAuto output stack trace with source fragments,
+// such as if a command line tool is run with --verbose flag.
+// Only error message is displayed otherwise.
+var Verbose = false
+
 var cache = map[string][]string{}
 
+// failures contains errors of source files which failed to read or timed out,
+// so they are not read again on every output.
+var failures = map[string]error{}
+
 var mutex sync.RWMutex
 
 // Print prints error message with stack trace.
 func Print(err error) {
-	fmt.Println(Sprint(err))
+	fmt.Print(Sprint(err) + LineSeparator)
 }
 
 // PrintSource prints error message with stack trace and source fragments.
@@ -33,29 +71,83 @@ func Print(err error) {
 // Pass two numbers to specify exactly how many lines should be shown
 // before and after traced line.
 func PrintSource(err error, nums ...int) {
-	fmt.Println(SprintSource(err, nums...))
+	fmt.Print(SprintSource(err, nums...) + LineSeparator)
 }
 
 // PrintSourceColor prints error message with stack trace and source fragments,
 // which are in color.
 // Output rules are the same as in PrintSource.
 func PrintSourceColor(err error, nums ...int) {
-	fmt.Println(SprintSourceColor(err, nums...))
+	fmt.Print(SprintSourceColor(err, nums...) + LineSeparator)
+}
+
+// PrintAuto prints error message with stack trace and source fragments
+// if Verbose is true or error message only otherwise.
+func PrintAuto(err error) {
+	fmt.Print(SprintAuto(err) + LineSeparator)
 }
 
 // Sprint returns error output by the same rules as Print.
 func Sprint(err error) string {
-	return sprint(err, []int{0}, false)
+	return sprint(err, []int{0}, false, nil)
+}
+
+// SprintWith returns error output by the same rules as Print,
+// where frames are formatted by format.
+// FrameFormat is used if format is nil.
+func SprintWith(err error, format func(Frame) string) string {
+	return sprint(err, []int{0}, false, format)
+}
+
+// SprintTrace returns additional messages of err and its stack trace
+// by the same rules as Sprint, but without the original error message,
+// which is useful when it is noisy or redundant.
+// It will be empty for an error with no messages and no stack trace.
+func SprintTrace(err error) string {
+	format := FrameFormat
+	if format == nil {
+		format = Frame.String
+	}
+	messages := Messages(err)
+	frames := StackTrace(err)
+	rows := make([]string, 0, len(messages)+len(frames))
+	for i, message := range messages {
+		if i == 0 {
+			message = ErrorPrefix + message
+		}
+		rows = append(rows, message)
+	}
+	for _, frame := range frames {
+		rows = append(rows, format(frame))
+	}
+	// Messages are truncated the same way as error message by Sprint.
+	head := strings.Join(rows[:len(messages)], LineSeparator)
+	return truncate(strings.Join(rows, LineSeparator), len(head))
+}
+
+// SprintAuto returns error output by the same rules as PrintAuto.
+func SprintAuto(err error) string {
+	if Verbose {
+		return SprintSource(err)
+	}
+	if err == nil {
+		return ""
+	}
+	message := ErrorPrefix + err.Error()
+	if e, ok := err.(Error); ok {
+		message = text(e)
+	}
+	return truncate(message, len(message))
 }
 
 // SprintSource returns error output by the same rules as PrintSource.
 func SprintSource(err error, nums ...int) string {
-	return sprint(err, nums, false)
+	return sprint(err, nums, false, nil)
 }
 
 // SprintSourceColor returns error output by the same rules as PrintSourceColor.
 func SprintSourceColor(err error, nums ...int) string {
-	return sprint(err, nums, true)
+	return sprint(err, nums, true, nil)
 }
 
 func calcRows(nums []int) (before, after int, withSource bool) {
@@ -89,26 +181,79 @@ func calcRows(nums []int) (before, after int, withSource bool) {
 func readLines(path string) ([]string, error) {
 	mutex.RLock()
 	lines, ok := cache[path]
+	failure := failures[path]
+	fsys := bundle
 	mutex.RUnlock()
 	if ok {
 		return lines, nil
 	}
+	if failure != nil {
+		return nil, failure
+	}
 
-	b, err := os.ReadFile(path)
+	b, err := readSource(fsys, path)
 	if err != nil {
-		return nil, fmt.Errorf("tracerr: file %s not found", path)
+		mutex.Lock()
+		defer mutex.Unlock()
+		failures[path] = err
+		return nil, err
 	}
 	lines = strings.Split(string(b), "\n")
+	// Source files with Windows line endings are displayed
+	// with LineSeparator the same way.
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
 	mutex.Lock()
 	defer mutex.Unlock()
 	cache[path] = lines
 	return lines, nil
 }
 
+// readSource reads a source file from bundle or disk
+// within SourceReadTimeout.
+func readSource(fsys fs.FS, path string) ([]byte, error) {
+	type result struct {
+		b   []byte
+		err error
+	}
+	read := func() result {
+		b, err := readBundle(fsys, path)
+		if err != nil {
+			b, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return result{err: fmt.Errorf("tracerr: file %s not found", path)}
+		}
+		return result{b: b}
+	}
+	timeout := SourceReadTimeout
+	if timeout <= 0 {
+		r := read()
+		return r.b, r.err
+	}
+	// Buffered, so reading goroutine never blocks after timeout.
+	done := make(chan result, 1)
+	go func() {
+		done <- read()
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.b, r.err
+	case <-timer.C:
+		return nil, fmt.Errorf("tracerr: file %s read timeout", path)
+	}
+}
+
 func sourceRows(rows []string, frame Frame, before, after int, colorized bool) []string {
 	lines, err := readLines(frame.Path)
 	if err != nil {
-		message := err.Error()
+		if SourceUnavailableFormat == "" {
+			return append(rows, "")
+		}
+		message := fmt.Sprintf(SourceUnavailableFormat, frame.Path)
 		if colorized {
 			message = yellow(message)
 		}
@@ -140,6 +285,9 @@ func sourceRows(rows []string, frame Frame, before, after int, colorized bool) [
 				message = red(message)
 			}
 		} else if colorized {
+			if SyntaxHighlight && strings.HasSuffix(frame.Path, ".go") {
+				line = highlightGo(line)
+			}
 			message = fmt.Sprintf("%s\t%s", black(strconv.Itoa(i+1)), line)
 		} else {
 			message = fmt.Sprintf("%d\t%s", i+1, line)
@@ -149,34 +297,63 @@ func sourceRows(rows []string, frame Frame, before, after int, colorized bool) [
 	return append(rows, "")
 }
 
-func sprint(err error, nums []int, colorized bool) string {
+// text returns error message with ErrorPrefix without stack trace.
+func text(e Error) string {
+	return ErrorPrefix + errText(e)
+}
+
+func sprint(err error, nums []int, colorized bool, format func(Frame) string) string {
 	if err == nil {
 		return ""
 	}
 	e, ok := err.(Error)
 	if !ok {
-		return err.Error()
+		message := ErrorPrefix + err.Error()
+		return truncate(message, len(message))
+	}
+	if format == nil {
+		format = FrameFormat
+	}
+	if format == nil {
+		format = Frame.String
 	}
 	before, after, withSource := calcRows(nums)
+	if data, ok := e.(*errorData); ok && data.noSource {
+		withSource = false
+	}
 	frames := e.StackTrace()
 	expectedRows := len(frames) + 1
 	if withSource {
 		expectedRows = (before+after+3)*len(frames) + 2
 	}
 	rows := make([]string, 0, expectedRows)
-	rows = append(rows, e.Error())
+	rows = append(rows, text(e))
 	if withSource {
 		rows = append(rows, "")
 	}
+	highlighted := !HighlightInApp
 	for _, frame := range frames {
-		message := frame.String()
+		message := format(frame)
+		marked := !highlighted && frame.InApp()
+		if marked {
+			highlighted = true
+		}
 		if colorized {
-			message = bold(message)
+			if marked {
+				message = yellow(message)
+			}
+			if DimTestFrames && frame.IsTest() {
+				message = dim(message)
+			} else {
+				message = bold(message)
+			}
+		} else if marked {
+			message = "> " + message
 		}
 		rows = append(rows, message)
 		if withSource {
 			rows = sourceRows(rows, frame, before, after, colorized)
 		}
 	}
-	return strings.Join(rows, "\n")
+	return truncate(strings.Join(rows, LineSeparator), len(rows[0]))
 }
@@ -365,10 +365,10 @@ func TestNoSourceFile(t *testing.T) {
 		"some error",
 		"",
 		"/tmp/not_exists.go:42 main.Foo()",
-		"tracerr: file /tmp/not_exists.go not found",
+		"// source unavailable: /tmp/not_exists.go",
 		"",
 		"/tmp/not_exists_2.go:43 main.Bar()",
-		"tracerr: file /tmp/not_exists_2.go not found",
+		"// source unavailable: /tmp/not_exists_2.go",
 		"",
 	}
 	expected := strings.Join(expectedRows, "\n")
@@ -401,10 +401,10 @@ func TestNoSourceFileColor(t *testing.T) {
 		"some error",
 		"",
 		bold("/tmp/not_exists.go:42 main.Foo()"),
-		yellow("tracerr: file /tmp/not_exists.go not found"),
+		yellow("// source unavailable: /tmp/not_exists.go"),
 		"",
 		bold("/tmp/not_exists_2.go:43 main.Bar()"),
-		yellow("tracerr: file /tmp/not_exists_2.go not found"),
+		yellow("// source unavailable: /tmp/not_exists_2.go"),
 		"",
 	}
 	expected := strings.Join(expectedRows, "\n")
@@ -416,6 +416,71 @@ func TestNoSourceFileColor(t *testing.T) {
 	}
 }
 
+func TestSourceUnavailableFormat(t *testing.T) {
+	err := tracerr.CustomError(
+		errors.New("some error"),
+		[]tracerr.Frame{
+			{
+				Func: "main.Foo",
+				Line: 9,
+				Path: "error_helper_test.go",
+			},
+			{
+				Func: "main.Bar",
+				Line: 43,
+				Path: "/tmp/not_exists.go",
+			},
+		},
+	)
+	defaultFormat := tracerr.SourceUnavailableFormat
+	defer func() {
+		tracerr.SourceUnavailableFormat = defaultFormat
+	}()
+
+	cases := []struct {
+		Format       string
+		ExpectedRows []string
+	}{
+		{
+			Format: "missing %s",
+			ExpectedRows: []string{
+				"some error",
+				"",
+				"error_helper_test.go:9 main.Foo()",
+				"9\t\treturn addFrameB(message)",
+				"",
+				"/tmp/not_exists.go:43 main.Bar()",
+				"missing /tmp/not_exists.go",
+				"",
+			},
+		},
+		{
+			Format: "",
+			ExpectedRows: []string{
+				"some error",
+				"",
+				"error_helper_test.go:9 main.Foo()",
+				"9\t\treturn addFrameB(message)",
+				"",
+				"/tmp/not_exists.go:43 main.Bar()",
+				"",
+			},
+		},
+	}
+
+	for i, c := range cases {
+		tracerr.SourceUnavailableFormat = c.Format
+		output := tracerr.SprintSource(err, 0, 0)
+		expected := strings.Join(c.ExpectedRows, "\n")
+		if output != expected {
+			t.Errorf(
+				"cases[%#v]: tracerr.SprintSource(err, 0, 0) = %#v; want %#v",
+				i, output, expected,
+			)
+		}
+	}
+}
+
 func assertRows(t *testing.T, i int, output string, expectedRows []string, extra int) {
 	rows := strings.Split(output, "\n")
 	// There must be at least "extra" frames of test runner.
@@ -429,7 +494,7 @@ func assertRows(t *testing.T, i int, output string, expectedRows []string, extra
 	// Remove root path, cause it could be different on different environments.
 	re := regexp.MustCompile("([^/]*)/.*(/src/github\\.com/ztrue/tracerr/.*)")
 	for j, expectedRow := range expectedRows {
-		row := re.ReplaceAllString(rows[j], "$1$2")
+		row := re.ReplaceAllString(normalizePath(rows[j]), "$1$2")
 		if row != expectedRow {
 			t.Errorf(
 				"case #%d: rows[%#v] = %#v; want %#v",
@@ -469,3 +534,155 @@ func red(in string) string {
 func yellow(in string) string {
 	return fmt.Sprintf("\x1b[33m%s\x1b[0m", in)
 }
+
+func firstLine(message string) string {
+	return strings.SplitN(message, "\n", 2)[0]
+}
+
+// normalizePath replaces working directory with a GOPATH-like one,
+// cause tests could be run outside of GOPATH.
+func normalizePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	return strings.Replace(path, wd, "/src/github.com/ztrue/tracerr", 1)
+}
+
+func TestBaseNamesOnly(t *testing.T) {
+	defer func() {
+		tracerr.BaseNamesOnly = false
+	}()
+	tracerr.BaseNamesOnly = true
+
+	frame := tracerr.Frame{Func: "main.foo", Line: 42, Path: "/src/github.com/john/doe/foobar.go"}
+	if frame.String() != "foobar.go:42 main.foo()" {
+		t.Errorf("frame.String() = %#v; want %#v", frame.String(), "foobar.go:42 main.foo()")
+	}
+
+	err := addFrameA("some error")
+	expectedRows := []string{
+		"some error",
+		"error_helper_test.go:17 github.com/ztrue/tracerr_test.addFrameC()",
+		"error_helper_test.go:13 github.com/ztrue/tracerr_test.addFrameB()",
+		"error_helper_test.go:9 github.com/ztrue/tracerr_test.addFrameA()",
+		"print_test.go:563 github.com/ztrue/tracerr_test.TestBaseNamesOnly()",
+	}
+	rows := strings.Split(tracerr.Sprint(err), "\n")
+	if len(rows) < len(expectedRows) {
+		t.Fatalf("len(rows) = %#v; want >= %#v", len(rows), len(expectedRows))
+	}
+	for i, expectedRow := range expectedRows {
+		if rows[i] != expectedRow {
+			t.Errorf("rows[%#v] = %#v; want %#v", i, rows[i], expectedRow)
+		}
+	}
+
+	expected := "16\tfunc addFrameC(message string) error {"
+	rows = strings.Split(tracerr.SprintSource(err, 1, 0), "\n")
+	if rows[3] != expected {
+		t.Errorf("rows[3] = %#v; want %#v", rows[3], expected)
+	}
+}
+
+func TestSprintWith(t *testing.T) {
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "main.read", Line: 12, Path: "/src/read.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	})
+	html := func(frame tracerr.Frame) string {
+		return fmt.Sprintf("<li>%s:%d</li>", frame.Func, frame.Line)
+	}
+	terminal := func(frame tracerr.Frame) string {
+		return fmt.Sprintf("at %s", frame.Func)
+	}
+	expectedHTML := "some error\n<li>main.read:12</li>\n<li>main.main:7</li>"
+	if output := tracerr.SprintWith(err, html); output != expectedHTML {
+		t.Errorf("tracerr.SprintWith(html) = %#v; want %#v", output, expectedHTML)
+	}
+	expectedTerminal := "some error\nat main.read\nat main.main"
+	if output := tracerr.SprintWith(err, terminal); output != expectedTerminal {
+		t.Errorf("tracerr.SprintWith(terminal) = %#v; want %#v", output, expectedTerminal)
+	}
+
+	defer func() {
+		tracerr.FrameFormat = nil
+	}()
+	tracerr.FrameFormat = terminal
+	if output := tracerr.SprintWith(err, nil); output != expectedTerminal {
+		t.Errorf("tracerr.SprintWith(nil) = %#v; want %#v", output, expectedTerminal)
+	}
+	if output := tracerr.Sprint(err); output != expectedTerminal {
+		t.Errorf("tracerr.Sprint() = %#v; want %#v", output, expectedTerminal)
+	}
+}
+
+func TestLineSeparator(t *testing.T) {
+	defer func() {
+		tracerr.LineSeparator = "\n"
+	}()
+	err := tracerr.Wrap(tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "github.com/ztrue/tracerr_test.addFrameC", Line: 17, Path: "error_helper_test.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	}), "failed")
+	for _, separator := range []string{"\n", "\r\n"} {
+		tracerr.LineSeparator = separator
+		lines := []string{
+			"failed",
+			"some error",
+			"\terror_helper_test.go:17 github.com/ztrue/tracerr_test.addFrameC()",
+			"\t/src/main.go:7 main.main()",
+		}
+		if output := err.Error(); output != strings.Join(lines, separator) {
+			t.Errorf("err.Error() = %#v; want %#v", output, strings.Join(lines, separator))
+		}
+		lines = []string{
+			"failed",
+			"some error",
+			"",
+			"error_helper_test.go:17 github.com/ztrue/tracerr_test.addFrameC()",
+			"16\tfunc addFrameC(message string) error {",
+			"17\t\treturn tracerr.New(message)",
+			"",
+			"/src/main.go:7 main.main()",
+			"// source unavailable: /src/main.go",
+			"",
+		}
+		if output := tracerr.SprintSource(err, 1, 0); output != strings.Join(lines, separator) {
+			t.Errorf("tracerr.SprintSource() = %#v; want %#v", output, strings.Join(lines, separator))
+		}
+	}
+}
+
+func TestErrorPrefix(t *testing.T) {
+	defer func() {
+		tracerr.ErrorPrefix = ""
+	}()
+	tracerr.ErrorPrefix = "error: "
+
+	err := tracerr.Wrap(tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	}), "failed")
+	cases := []struct {
+		Output   string
+		Expected string
+	}{
+		{Output: err.Error(), Expected: "error: failed\nsome error\n\t/src/main.go:7 main.main()"},
+		{Output: fmt.Sprintf("%v", err), Expected: "error: failed\nsome error\n\t/src/main.go:7 main.main()"},
+		{Output: fmt.Sprintf("%.0v", err), Expected: "error: failed\nsome error"},
+		{Output: tracerr.Quiet(err).Error(), Expected: "error: failed\nsome error"},
+		{Output: tracerr.Sprint(err), Expected: "error: failed\nsome error\n/src/main.go:7 main.main()"},
+		{
+			Output:   tracerr.SprintSource(err),
+			Expected: "error: failed\nsome error\n\n/src/main.go:7 main.main()\n// source unavailable: /src/main.go\n",
+		},
+		{Output: tracerr.Sprint(errors.New("regular error")), Expected: "error: regular error"},
+		// Prefix is added once for nested errors.
+		{Output: tracerr.Errorf("outer: %w", err).Error(), Expected: "error: outer: failed\nsome error\n\t/src/main.go:7 main.main()"},
+	}
+	for i, c := range cases {
+		if c.Output != c.Expected {
+			t.Errorf("cases[%#v]: output = %#v; want %#v", i, c.Output, c.Expected)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package tracerr
+
+// Quiet returns a copy of err, which Error() returns error message
+// without stack trace, e.g. for user-facing responses.
+// Stack trace is still available by StackTrace, Print and Sprint functions.
+// The original error is not modified.
+//
+// Stack trace is added if err is not of type Error
+// and it will be nil if err is nil.
+func Quiet(err error) Error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(*errorData)
+	if !ok {
+		e = trace(err, "", 2).(*errorData)
+	}
+	c := e.clone()
+	c.quiet = true
+	return c
+}
@@ -0,0 +1,38 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestQuiet(t *testing.T) {
+	err := tracerr.Wrap(errors.New("not found"), "failed to read")
+	quiet := tracerr.Quiet(err)
+	expected := "failed to read\nnot found"
+	if quiet.Error() != expected {
+		t.Errorf("quiet.Error() = %#v; want %#v", quiet.Error(), expected)
+	}
+	if output := fmt.Sprintf("%v", quiet); output != expected {
+		t.Errorf("fmt.Sprintf(%%v) = %#v; want %#v", output, expected)
+	}
+	if len(quiet.StackTrace()) == 0 || quiet.StackTrace()[0] != err.StackTrace()[0] {
+		t.Errorf("quiet.StackTrace() = %#v; want %#v", quiet.StackTrace(), err.StackTrace())
+	}
+	if rows := strings.Split(tracerr.Sprint(quiet), "\n"); len(rows) != len(err.StackTrace())+2 {
+		t.Errorf("tracerr.Sprint() = %#v; want frames", rows)
+	}
+	if !strings.Contains(err.Error(), "\t") {
+		t.Errorf("err.Error() = %#v; want original error not modified", err.Error())
+	}
+
+	if quiet := tracerr.Quiet(errors.New("regular")); quiet.Error() != "regular" || len(quiet.StackTrace()) == 0 {
+		t.Errorf("tracerr.Quiet(regular) = %#v; want error with stack trace", quiet)
+	}
+	if quiet := tracerr.Quiet(nil); quiet != nil {
+		t.Errorf("tracerr.Quiet(nil) = %#v; want nil", quiet)
+	}
+}
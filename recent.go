@@ -0,0 +1,60 @@
+package tracerr
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	recentEnabled atomic.Bool
+	recentMutex   sync.Mutex
+	recentErrors  []Error
+	// recentNext is an index of the oldest error in a full buffer.
+	recentNext int
+)
+
+// EnableRecentBuffer turns on keeping of the last n errors
+// with captured stack trace in memory, e.g. for a debugging endpoint,
+// see RecentErrors. Errors kept before are dropped.
+// It is turned off if n is not positive.
+func EnableRecentBuffer(n int) {
+	recentMutex.Lock()
+	defer recentMutex.Unlock()
+	recentNext = 0
+	if n <= 0 {
+		recentEnabled.Store(false)
+		recentErrors = nil
+		return
+	}
+	recentErrors = make([]Error, 0, n)
+	recentEnabled.Store(true)
+}
+
+// RecentErrors returns errors kept since EnableRecentBuffer is called,
+// the oldest first.
+func RecentErrors() []Error {
+	recentMutex.Lock()
+	defer recentMutex.Unlock()
+	errs := make([]Error, 0, len(recentErrors))
+	errs = append(errs, recentErrors[recentNext:]...)
+	return append(errs, recentErrors[:recentNext]...)
+}
+
+// keepRecent adds e to the buffer of recent errors
+// if EnableRecentBuffer is called.
+func keepRecent(e Error) {
+	if !recentEnabled.Load() {
+		return
+	}
+	recentMutex.Lock()
+	defer recentMutex.Unlock()
+	if len(recentErrors) < cap(recentErrors) {
+		recentErrors = append(recentErrors, e)
+		return
+	}
+	if len(recentErrors) == 0 {
+		return
+	}
+	recentErrors[recentNext] = e
+	recentNext = (recentNext + 1) % len(recentErrors)
+}
@@ -0,0 +1,67 @@
+package tracerr_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestRecentErrors(t *testing.T) {
+	defer tracerr.EnableRecentBuffer(0)
+
+	tracerr.New("not kept")
+	if errs := tracerr.RecentErrors(); len(errs) != 0 {
+		t.Errorf("tracerr.RecentErrors() = %#v; want empty before EnableRecentBuffer", errs)
+	}
+
+	n := 10
+	tracerr.EnableRecentBuffer(n)
+	for i := 0; i < n+5; i++ {
+		tracerr.New(fmt.Sprintf("error %d", i))
+	}
+	errs := tracerr.RecentErrors()
+	if len(errs) != n {
+		t.Fatalf("len(tracerr.RecentErrors()) = %d; want %d", len(errs), n)
+	}
+	for i, err := range errs {
+		expected := fmt.Sprintf("error %d", i+5)
+		if message := tracerr.RootMessage(err); message != expected {
+			t.Errorf("errs[%d] = %#v; want %#v", i, message, expected)
+		}
+	}
+
+	tracerr.EnableRecentBuffer(n)
+	tracerr.New("error 0")
+	if errs := tracerr.RecentErrors(); len(errs) != 1 || tracerr.RootMessage(errs[0]) != "error 0" {
+		t.Errorf("tracerr.RecentErrors() = %#v; want one error after reset", errs)
+	}
+
+	tracerr.EnableRecentBuffer(0)
+	tracerr.New("not kept")
+	if errs := tracerr.RecentErrors(); len(errs) != 0 {
+		t.Errorf("tracerr.RecentErrors() = %#v; want empty after disabling", errs)
+	}
+}
+
+func TestRecentErrorsConcurrent(t *testing.T) {
+	defer tracerr.EnableRecentBuffer(0)
+
+	tracerr.EnableRecentBuffer(5)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				tracerr.New("some error")
+				tracerr.RecentErrors()
+			}
+		}()
+	}
+	wg.Wait()
+	if errs := tracerr.RecentErrors(); len(errs) != 5 {
+		t.Errorf("len(tracerr.RecentErrors()) = %d; want 5", len(errs))
+	}
+}
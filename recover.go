@@ -0,0 +1,65 @@
+package tracerr
+
+import (
+	"fmt"
+)
+
+// RecoverPanic converts value returned by recover() to an error
+// with stack trace, which is nil if there is no panic:
+//
+//	if r := recover(); r != nil {
+//		err = tracerr.RecoverPanic(r)
+//	}
+//
+// Value is used as an error if it is an error,
+// it is kept as is anyway and can be retrieved by PanicValue.
+func RecoverPanic(r interface{}) Error {
+	return recoverPanic(r, 2)
+}
+
+// RecoverInto recovers a panic and stores it to err as an error
+// with stack trace, err is not changed if there is no panic.
+// It must be deferred directly:
+//
+//	func run() (err error) {
+//		defer tracerr.RecoverInto(&err)
+//		// ...
+//	}
+func RecoverInto(err *error) {
+	r := recover()
+	if r == nil || err == nil {
+		return
+	}
+	*err = recoverPanic(r, 2)
+}
+
+// PanicValue returns the original value of a recovered panic
+// of the outermost error in the chain of err created by RecoverPanic or RecoverInto.
+// It will be nil and false if there is no such error.
+func PanicValue(err error) (interface{}, bool) {
+	var value interface{}
+	found := false
+	walk(err, func(current error) bool {
+		e, ok := current.(*errorData)
+		if ok && e.panicked {
+			value, found = e.recovered, true
+			return false
+		}
+		return true
+	})
+	return value, found
+}
+
+func recoverPanic(r interface{}, skip int) Error {
+	if r == nil {
+		return nil
+	}
+	err, ok := r.(error)
+	if !ok {
+		err = fmt.Errorf("%v", r)
+	}
+	e := trace(err, "", skip+1).(*errorData)
+	e.recovered = r
+	e.panicked = true
+	return e
+}
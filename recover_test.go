@@ -0,0 +1,65 @@
+package tracerr_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+type panicPayload struct {
+	Code   int
+	Fields []string
+}
+
+func panicWithPayload() (err error) {
+	defer tracerr.RecoverInto(&err)
+	panic(panicPayload{Code: 42, Fields: []string{"name"}})
+}
+
+func panicWithError() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = tracerr.RecoverPanic(r)
+		}
+	}()
+	panic(io.EOF)
+}
+
+func TestRecoverInto(t *testing.T) {
+	err := panicWithPayload()
+	if err == nil {
+		t.Fatalf("err = nil; want recovered panic")
+	}
+	if msg := tracerr.Unwrap(err).Error(); msg != "{42 [name]}" {
+		t.Errorf("tracerr.Unwrap(err).Error() = %#v; want %#v", msg, "{42 [name]}")
+	}
+	value, ok := tracerr.PanicValue(tracerr.Wrap(err, "outer"))
+	if !ok {
+		t.Fatalf("tracerr.PanicValue() ok = false; want true")
+	}
+	payload, ok := value.(panicPayload)
+	if !ok || payload.Code != 42 || len(payload.Fields) != 1 || payload.Fields[0] != "name" {
+		t.Errorf("tracerr.PanicValue() = %#v; want %#v", value, panicPayload{Code: 42, Fields: []string{"name"}})
+	}
+	if len(tracerr.StackTrace(err)) == 0 {
+		t.Errorf("tracerr.StackTrace(err) is empty; want frames")
+	}
+}
+
+func TestRecoverPanic(t *testing.T) {
+	err := panicWithError()
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("err = %#v; want io.EOF", err)
+	}
+	if value, ok := tracerr.PanicValue(err); !ok || value != io.EOF {
+		t.Errorf("tracerr.PanicValue() = %#v, %#v; want io.EOF, true", value, ok)
+	}
+	if err := tracerr.RecoverPanic(nil); err != nil {
+		t.Errorf("tracerr.RecoverPanic(nil) = %#v; want nil", err)
+	}
+	if value, ok := tracerr.PanicValue(tracerr.New("regular")); ok || value != nil {
+		t.Errorf("tracerr.PanicValue(regular) = %#v, %#v; want nil, false", value, ok)
+	}
+}
@@ -0,0 +1,36 @@
+package tracerr
+
+// WithRetries returns a copy of err with number of attempts attached,
+// which were made by an operation before giving up.
+// The original error is not modified.
+//
+// Stack trace is added if err is not of type Error
+// and it will be nil if err is nil.
+func WithRetries(err error, n int) Error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(*errorData)
+	if !ok {
+		e = trace(err, "", 2).(*errorData)
+	}
+	c := e.clone()
+	c.retries = n
+	return c
+}
+
+// RetriesOf returns number of attempts attached to the outermost error
+// in the chain of err by WithRetries.
+// It will be 0 and false if there is no number of attempts.
+func RetriesOf(err error) (int, bool) {
+	retries, found := 0, false
+	walk(err, func(current error) bool {
+		e, ok := current.(*errorData)
+		if ok && e.retries != 0 {
+			retries, found = e.retries, true
+			return false
+		}
+		return true
+	})
+	return retries, found
+}
@@ -0,0 +1,39 @@
+package tracerr_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestRetriesOf(t *testing.T) {
+	if tracerr.WithRetries(nil, 3) != nil {
+		t.Errorf("tracerr.WithRetries(nil, ...) = non-nil; want nil")
+	}
+	if n, ok := tracerr.RetriesOf(tracerr.New("some error")); n != 0 || ok {
+		t.Errorf("tracerr.RetriesOf() = %d, %t; want 0, false", n, ok)
+	}
+
+	var err error = errors.New("timeout")
+	for i := 0; i < 3; i++ {
+		n, _ := tracerr.RetriesOf(err)
+		err = tracerr.WithRetries(err, n+1)
+		err = fmt.Errorf("attempt %d: %w", i+1, err)
+	}
+	err = tracerr.Wrap(err, "failed to connect")
+	if n, ok := tracerr.RetriesOf(err); n != 3 || !ok {
+		t.Errorf("tracerr.RetriesOf() = %d, %t; want 3, true", n, ok)
+	}
+
+	b, jsonErr := json.Marshal(tracerr.WithRetries(errors.New("timeout"), 3))
+	if jsonErr != nil {
+		t.Fatalf("json.Marshal() error: %s", jsonErr)
+	}
+	if !strings.HasSuffix(string(b), `,"retries":3}`) {
+		t.Errorf("json.Marshal() = %s; want retries", b)
+	}
+}
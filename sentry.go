@@ -0,0 +1,25 @@
+package tracerr
+
+// ToSentryFrames converts stack trace of err to frames of Sentry stack trace,
+// which can be passed to Sentry SDK as is.
+// Frames are ordered the oldest call first, as Sentry expects,
+// in_app is true if frame is in app, see InApp.
+// Result is empty if err has no stack trace.
+func ToSentryFrames(err error) []map[string]interface{} {
+	frames := StackTrace(err)
+	converted := make([]map[string]interface{}, len(frames))
+	for i, frame := range frames {
+		filename := relPath(frame)
+		if filename == "" {
+			filename = frame.Path
+		}
+		converted[len(frames)-1-i] = map[string]interface{}{
+			"function": frame.Func,
+			"filename": filename,
+			"abs_path": frame.Path,
+			"lineno":   frame.Line,
+			"in_app":   frame.InApp(),
+		}
+	}
+	return converted
+}
@@ -0,0 +1,56 @@
+package tracerr_test
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestToSentryFrames(t *testing.T) {
+	err := addFrameA("some error")
+	frames := tracerr.StackTrace(err)
+	sentryFrames := tracerr.ToSentryFrames(err)
+	if len(sentryFrames) != len(frames) {
+		t.Fatalf("len(sentryFrames) = %d; want %d", len(sentryFrames), len(frames))
+	}
+	expectedKeys := []string{"abs_path", "filename", "function", "in_app", "lineno"}
+	for i, sentryFrame := range sentryFrames {
+		keys := make([]string, 0, len(sentryFrame))
+		for key := range sentryFrame {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		if !reflect.DeepEqual(keys, expectedKeys) {
+			t.Errorf("sentryFrames[%d] keys = %#v; want %#v", i, keys, expectedKeys)
+		}
+		frame := frames[len(frames)-1-i]
+		if sentryFrame["function"] != frame.Func {
+			t.Errorf("sentryFrames[%d][\"function\"] = %#v; want %#v", i, sentryFrame["function"], frame.Func)
+		}
+		if sentryFrame["abs_path"] != frame.Path {
+			t.Errorf("sentryFrames[%d][\"abs_path\"] = %#v; want %#v", i, sentryFrame["abs_path"], frame.Path)
+		}
+		if sentryFrame["lineno"] != frame.Line {
+			t.Errorf("sentryFrames[%d][\"lineno\"] = %#v; want %#v", i, sentryFrame["lineno"], frame.Line)
+		}
+	}
+	top := sentryFrames[len(sentryFrames)-1]
+	if top["function"] != "github.com/ztrue/tracerr_test.addFrameC" {
+		t.Errorf("top function = %#v; want addFrameC", top["function"])
+	}
+	if top["filename"] != "error_helper_test.go" {
+		t.Errorf("top filename = %#v; want %#v", top["filename"], "error_helper_test.go")
+	}
+	if top["in_app"] != true {
+		t.Errorf("top in_app = %#v; want true", top["in_app"])
+	}
+	if bottom := sentryFrames[0]; bottom["function"] != "runtime.goexit" || bottom["in_app"] != false {
+		t.Errorf("bottom frame = %#v; want runtime.goexit not in app", bottom)
+	}
+	if sentryFrames := tracerr.ToSentryFrames(errors.New("regular error")); len(sentryFrames) != 0 {
+		t.Errorf("tracerr.ToSentryFrames() = %#v; want empty", sentryFrames)
+	}
+}
@@ -0,0 +1,33 @@
+package tracerr
+
+import (
+	"strings"
+)
+
+// CaptureSite returns the exact place where stack trace of err is captured,
+// which is the call of New, Wrap or a similar function,
+// rather than the place where err is returned.
+// It will be false if err is not of type Error or its stack trace is empty.
+func CaptureSite(err error) (Frame, bool) {
+	frames := StackTrace(err)
+	if len(frames) == 0 {
+		return Frame{}, false
+	}
+	return frames[0], true
+}
+
+// IsReturnSite checks by source code if frame points to a return statement,
+// e.g. "return tracerr.Wrap(err, "")", so the capture site is also
+// the place where an error is returned.
+// It will be false if source is not available.
+func IsReturnSite(frame Frame) bool {
+	if frame.Path == "" || frame.Line <= 0 {
+		return false
+	}
+	lines, err := readLines(frame.Path)
+	if err != nil || frame.Line > len(lines) {
+		return false
+	}
+	line := strings.TrimSpace(lines[frame.Line-1])
+	return line == "return" || strings.HasPrefix(line, "return ")
+}
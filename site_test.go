@@ -0,0 +1,72 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestCaptureSite(t *testing.T) {
+	frames := []tracerr.Frame{
+		{Func: "runtime.sigpanic", Line: 10, Path: "/go/src/runtime/signal_unix.go"},
+		{Func: "main.read", Line: 42, Path: "/src/read.go"},
+	}
+	cases := []struct {
+		Error    error
+		Expected tracerr.Frame
+		OK       bool
+	}{
+		{Error: nil},
+		{Error: errors.New("regular error")},
+		{Error: tracerr.CustomError(errors.New("some error"), nil)},
+		{
+			Error:    tracerr.CustomError(errors.New("some error"), frames),
+			Expected: frames[0],
+			OK:       true,
+		},
+	}
+	for i, c := range cases {
+		frame, ok := tracerr.CaptureSite(c.Error)
+		if frame != c.Expected || ok != c.OK {
+			t.Errorf(
+				"cases[%#v]: tracerr.CaptureSite() = %#v, %t; want %#v, %t",
+				i, frame, ok, c.Expected, c.OK,
+			)
+		}
+	}
+}
+
+func TestIsReturnSite(t *testing.T) {
+	cases := []struct {
+		Error    error
+		Expected bool
+	}{
+		{Error: returnSiteError(), Expected: true},
+		{Error: assignSiteError(), Expected: false},
+		{
+			Error: tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+				{Func: "main.read", Line: 42, Path: "/tmp/not_existing_file.go"},
+			}),
+			Expected: false,
+		},
+	}
+	for i, c := range cases {
+		frame, ok := tracerr.CaptureSite(c.Error)
+		if !ok {
+			t.Fatalf("cases[%#v]: tracerr.CaptureSite() is not ok", i)
+		}
+		if isReturn := tracerr.IsReturnSite(frame); isReturn != c.Expected {
+			t.Errorf("cases[%#v]: tracerr.IsReturnSite(%#v) = %t; want %t", i, frame, isReturn, c.Expected)
+		}
+	}
+}
+
+func returnSiteError() error {
+	return tracerr.New("some error")
+}
+
+func assignSiteError() error {
+	err := tracerr.New("some error")
+	return err
+}
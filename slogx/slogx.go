@@ -0,0 +1,87 @@
+// Package slogx logs errors of tracerr with log/slog.
+//
+// It is a separate package to keep tracerr free of slog dependency.
+package slogx
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/ztrue/tracerr"
+)
+
+func init() {
+	// Errors are wrapped at the place where WrapLog is called.
+	tracerr.RegisterWrapper("github.com/ztrue/tracerr/slogx.WrapLog")
+}
+
+// WrapLog adds stacktrace to err the same way as tracerr.Wrap,
+// logs it at error level and returns it, so it can still be propagated.
+// Nothing is logged if err is nil.
+//
+// Log message is message or the original error message if it is empty.
+func WrapLog(logger *slog.Logger, err error, message string) tracerr.Error {
+	wrapped := tracerr.Wrap(err, message)
+	if wrapped == nil {
+		return nil
+	}
+	if message == "" {
+		message = tracerr.RootMessage(wrapped)
+	}
+	logger.Error(message, Attr(wrapped))
+	return wrapped
+}
+
+// Attr returns "error" attribute with error message and stack trace of err.
+func Attr(err error) slog.Attr {
+	return slog.Any("error", logValuer{err: err})
+}
+
+// logValuer implements slog.LogValuer for an error.
+type logValuer struct {
+	err error
+}
+
+// LogValue returns a group of error message and stack trace.
+func (v logValuer) LogValue() slog.Value {
+	if v.err == nil {
+		return slog.Value{}
+	}
+	frames := tracerr.StackTrace(v.err)
+	stack := make([]string, len(frames))
+	for i, frame := range frames {
+		stack[i] = frame.String()
+	}
+	// Precision of other errors cuts their message.
+	message := v.err.Error()
+	if _, ok := v.err.(tracerr.Error); ok {
+		message = fmt.Sprintf("%.0v", v.err)
+	}
+	attrs := []slog.Attr{
+		slog.String("message", message),
+		slog.Any("stack", stack),
+	}
+	if retries, ok := tracerr.RetriesOf(v.err); ok {
+		attrs = append(attrs, slog.Int("retries", retries))
+	}
+	if annotations := tracerr.MergedAnnotations(v.err); len(annotations) > 0 {
+		attrs = append(attrs, slog.Any("annotations", annotationsValue(annotations)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// annotationsValue returns a group of annotations sorted by key,
+// so output is the same for the same error.
+func annotationsValue(annotations map[string]interface{}) slog.Value {
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, len(keys))
+	for i, key := range keys {
+		attrs[i] = slog.Any(key, annotations[key])
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,129 @@
+package slogx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+	"github.com/ztrue/tracerr/slogx"
+)
+
+func TestWrapLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	err := slogx.WrapLog(logger, errors.New("not found"), "failed to read")
+	if err == nil {
+		t.Fatalf("slogx.WrapLog() = nil; want error")
+	}
+	if frame := err.StackTrace()[0]; frame.Func != "github.com/ztrue/tracerr/slogx_test.TestWrapLog" {
+		t.Errorf("err.StackTrace()[0] = %#v; want TestWrapLog", frame)
+	}
+
+	var record struct {
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+		Error struct {
+			Message string   `json:"message"`
+			Stack   []string `json:"stack"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("json.Unmarshal(%#v) error: %s", buf.String(), err)
+	}
+	if record.Level != "ERROR" || record.Msg != "failed to read" {
+		t.Errorf("record = %#v; want error level with message", record)
+	}
+	if record.Error.Message != "failed to read\nnot found" {
+		t.Errorf("record.Error.Message = %#v; want %#v", record.Error.Message, "failed to read\nnot found")
+	}
+	if len(record.Error.Stack) != len(err.StackTrace()) ||
+		!strings.HasSuffix(record.Error.Stack[0], " github.com/ztrue/tracerr/slogx_test.TestWrapLog()") {
+		t.Errorf("record.Error.Stack = %#v; want stack trace", record.Error.Stack)
+	}
+
+	buf.Reset()
+	if err := slogx.WrapLog(logger, nil, "failed"); err != nil {
+		t.Errorf("slogx.WrapLog(nil) = %#v; want nil", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("output = %#v; want nothing logged", buf.String())
+	}
+}
+
+func TestAttrRetries(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("failed", slogx.Attr(tracerr.WithRetries(errors.New("timeout"), 3)))
+
+	var record struct {
+		Error struct {
+			Retries int `json:"retries"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("json.Unmarshal(%#v) error: %s", buf.String(), err)
+	}
+	if record.Error.Retries != 3 {
+		t.Errorf("record.Error.Retries = %d; want 3", record.Error.Retries)
+	}
+}
+
+func TestAttrAnnotations(t *testing.T) {
+	var err error = errors.New("some error")
+	for _, key := range []string{"user", "attempt", "region", "zone", "bucket", "id"} {
+		err = tracerr.Annotate(err, key, key+" value")
+	}
+	var first string
+	for i := 0; i < 20; i++ {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+		logger.Error("failed", slogx.Attr(err))
+		if i == 0 {
+			first = buf.String()
+			continue
+		}
+		if buf.String() != first {
+			t.Fatalf("output = %#v; want %#v", buf.String(), first)
+		}
+	}
+	expected := "error.annotations.attempt=\"attempt value\" error.annotations.bucket=\"bucket value\" " +
+		"error.annotations.id=\"id value\" error.annotations.region=\"region value\" " +
+		"error.annotations.user=\"user value\" error.annotations.zone=\"zone value\"\n"
+	if !strings.HasSuffix(first, expected) {
+		t.Errorf("output = %#v; want suffix %#v", first, expected)
+	}
+}
+
+func TestAttrPlainError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("failed", slogx.Attr(errors.New("some error")))
+
+	var record struct {
+		Error struct {
+			Message string   `json:"message"`
+			Stack   []string `json:"stack"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("json.Unmarshal(%#v) error: %s", buf.String(), err)
+	}
+	if record.Error.Message != "some error" {
+		t.Errorf("record.Error.Message = %#v; want %#v", record.Error.Message, "some error")
+	}
+	if len(record.Error.Stack) != 0 {
+		t.Errorf("record.Error.Stack = %#v; want empty", record.Error.Stack)
+	}
+}
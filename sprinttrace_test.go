@@ -0,0 +1,46 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestSprintTrace(t *testing.T) {
+	frames := []tracerr.Frame{
+		{Func: "main.readFile", Line: 42, Path: "/src/read.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	}
+	err := tracerr.CustomError(errors.New("open /tmp/config.json: no such file or directory"), frames)
+	cases := []struct {
+		Error    error
+		Expected string
+	}{
+		{
+			Error:    nil,
+			Expected: "",
+		},
+		{
+			Error:    errors.New("regular error"),
+			Expected: "",
+		},
+		{
+			Error: err,
+			Expected: "/src/read.go:42 main.readFile()\n" +
+				"/src/main.go:7 main.main()",
+		},
+		{
+			Error: tracerr.Wrap(tracerr.Wrap(err, "failed to read config"), "failed to start"),
+			Expected: "failed to start\n" +
+				"failed to read config\n" +
+				"/src/read.go:42 main.readFile()\n" +
+				"/src/main.go:7 main.main()",
+		},
+	}
+	for i, c := range cases {
+		if output := tracerr.SprintTrace(c.Error); output != c.Expected {
+			t.Errorf("cases[%#v]: tracerr.SprintTrace() = %#v; want %#v", i, output, c.Expected)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package tracerr
+
+// defaultStatus is http.StatusInternalServerError,
+// net/http is not imported to keep dependencies light.
+const defaultStatus = 500
+
+// WithStatus returns a copy of err with HTTP status code attached.
+// The original error is not modified.
+//
+// Stack trace is added if err is not of type Error
+// and it will be nil if err is nil.
+func WithStatus(err error, code int) Error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(*errorData)
+	if !ok {
+		e = trace(err, "", 2).(*errorData)
+	}
+	c := e.clone()
+	c.status = code
+	return c
+}
+
+// StatusOf returns HTTP status code attached to the outermost error
+// in the chain of err by WithStatus.
+// It will be 500 and false if there is no status code.
+func StatusOf(err error) (int, bool) {
+	status, found := defaultStatus, false
+	walk(err, func(current error) bool {
+		e, ok := current.(*errorData)
+		if ok && e.status != 0 {
+			status, found = e.status, true
+			return false
+		}
+		return true
+	})
+	return status, found
+}
@@ -0,0 +1,81 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestStatusOf(t *testing.T) {
+	if tracerr.WithStatus(nil, http.StatusNotFound) != nil {
+		t.Errorf("tracerr.WithStatus(nil, ...) = non-nil; want nil")
+	}
+
+	notFound := tracerr.WithStatus(errors.New("user not found"), http.StatusNotFound)
+	cases := []struct {
+		Error          error
+		ExpectedStatus int
+		ExpectedOk     bool
+	}{
+		{
+			Error:          nil,
+			ExpectedStatus: http.StatusInternalServerError,
+			ExpectedOk:     false,
+		},
+		{
+			Error:          tracerr.New("some error"),
+			ExpectedStatus: http.StatusInternalServerError,
+			ExpectedOk:     false,
+		},
+		{
+			Error:          notFound,
+			ExpectedStatus: http.StatusNotFound,
+			ExpectedOk:     true,
+		},
+		{
+			Error:          tracerr.Annotate(fmt.Errorf("get user: %w", notFound), "user", 42),
+			ExpectedStatus: http.StatusNotFound,
+			ExpectedOk:     true,
+		},
+		{
+			Error:          tracerr.WithStatus(notFound, http.StatusGone),
+			ExpectedStatus: http.StatusGone,
+			ExpectedOk:     true,
+		},
+	}
+	for i, c := range cases {
+		status, ok := tracerr.StatusOf(c.Error)
+		if status != c.ExpectedStatus || ok != c.ExpectedOk {
+			t.Errorf(
+				"tracerr.StatusOf(cases[%#v].Error) = %#v, %#v; want %#v, %#v",
+				i, status, ok, c.ExpectedStatus, c.ExpectedOk,
+			)
+		}
+	}
+}
+
+func TestStatusMiddleware(t *testing.T) {
+	handler := func(r *http.Request) error {
+		err := tracerr.WithStatus(errors.New("user not found"), http.StatusNotFound)
+		return tracerr.Annotate(fmt.Errorf("handle %s: %w", r.URL.Path, err), "path", r.URL.Path)
+	}
+	middleware := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := handler(r); err != nil {
+			status, _ := tracerr.StatusOf(err)
+			http.Error(w, http.StatusText(status), status)
+		}
+	})
+
+	recorder := httptest.NewRecorder()
+	middleware.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf(
+			"recorder.Code = %#v; want %#v",
+			recorder.Code, http.StatusNotFound,
+		)
+	}
+}
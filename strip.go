@@ -0,0 +1,26 @@
+package tracerr
+
+// StripTrace returns err without stack traces, e.g. to pass it over
+// a trust boundary, where stack traces should not leak.
+// Message of the result is the same as of err without stack trace
+// and it still matches by errors.Is and errors.As,
+// since it wraps the original error.
+//
+// Only errors created by tracerr are replaced,
+// an error of other type is returned as is with errors it wraps,
+// which may still contain errors created by tracerr deeper in the chain.
+// It will be nil if err is nil.
+func StripTrace(err error) error {
+	switch e := err.(type) {
+	case *errorData:
+		inner := StripTrace(e.err)
+		text := e.text()
+		if text == inner.Error() {
+			return inner
+		}
+		return trimmedError{err: inner, text: text}
+	case textError:
+		return StripTrace(e.e)
+	}
+	return err
+}
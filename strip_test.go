@@ -0,0 +1,55 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestStripTrace(t *testing.T) {
+	if tracerr.StripTrace(nil) != nil {
+		t.Errorf("tracerr.StripTrace(nil) = non-nil; want nil")
+	}
+	pathErr := &fs.PathError{Op: "open", Path: "/tmp/config", Err: fs.ErrNotExist}
+	cases := []struct {
+		Err      error
+		Expected string
+	}{
+		{Err: tracerr.Wrap(pathErr, ""), Expected: "open /tmp/config: file does not exist"},
+		{
+			Err:      tracerr.Wrap(tracerr.Wrap(pathErr, "failed to read"), "failed to load"),
+			Expected: "failed to load\nfailed to read\nopen /tmp/config: file does not exist",
+		},
+		{
+			Err:      tracerr.Errorf("failed to load: %w", tracerr.Wrap(pathErr, "")),
+			Expected: "failed to load: open /tmp/config: file does not exist",
+		},
+		{Err: pathErr, Expected: "open /tmp/config: file does not exist"},
+	}
+
+	for i, c := range cases {
+		err := tracerr.StripTrace(c.Err)
+		if err.Error() != c.Expected {
+			t.Errorf("cases[%#v]: err.Error() = %#v; want %#v", i, err.Error(), c.Expected)
+		}
+		if tracerr.StackTrace(err) != nil {
+			t.Errorf("cases[%#v]: tracerr.StackTrace(err) = %#v; want nil", i, tracerr.StackTrace(err))
+		}
+		if _, ok := err.(tracerr.Error); ok {
+			t.Errorf("cases[%#v]: err is tracerr.Error; want plain error", i)
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("cases[%#v]: errors.Is(err, fs.ErrNotExist) = false; want true", i)
+		}
+		var target *fs.PathError
+		if !errors.As(err, &target) || target != pathErr {
+			t.Errorf("cases[%#v]: errors.As(err, &target) = %#v; want %#v", i, target, pathErr)
+		}
+	}
+	if text := fmt.Sprint(tracerr.StripTrace(tracerr.New("some error"))); text != "some error" {
+		t.Errorf("fmt.Sprint() = %#v; want %#v", text, "some error")
+	}
+}
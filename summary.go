@@ -0,0 +1,69 @@
+package tracerr
+
+// SummaryFrames returns at most n most informative frames of err
+// for a compact but representative stack trace, e.g. for alerts.
+// Frames of Go runtime are skipped unless there are no other frames.
+//
+// Frames are selected in the following order:
+//
+//   - origin: the top frame of the deepest error in the chain created by tracerr,
+//     where an error is created, if it is not the same as the top frame;
+//   - top: the top frame of err, where an error is wrapped last time;
+//   - bottom: the last frame of err, which is an entry point of the call path;
+//   - frames of err between top and bottom, evenly spaced.
+//
+// Selected frames are returned in the same order as in stack trace,
+// preceded by origin. It will be nil if n is not positive or err has no stack trace.
+func SummaryFrames(err error, n int) []Frame {
+	if n <= 0 {
+		return nil
+	}
+	frames := userFrames(StackTrace(err))
+	var deepest []Frame
+	walk(err, func(current error) bool {
+		if e, ok := current.(*errorData); ok {
+			deepest = e.StackTrace()
+		}
+		return true
+	})
+	origin := userFrames(deepest)
+	var summary []Frame
+	if len(origin) > 0 && (len(frames) == 0 || origin[0] != frames[0]) {
+		summary = append(summary, origin[0])
+		n--
+	}
+	return append(summary, sampleFrames(frames, n)...)
+}
+
+// userFrames returns frames which are not a part of Go runtime,
+// or all frames if there are no such frames.
+func userFrames(frames []Frame) []Frame {
+	filtered := make([]Frame, 0, len(frames))
+	for _, frame := range frames {
+		if !IsRuntime(frame) {
+			filtered = append(filtered, frame)
+		}
+	}
+	if len(filtered) == 0 {
+		return frames
+	}
+	return filtered
+}
+
+// sampleFrames returns n evenly spaced frames including the first and the last ones.
+func sampleFrames(frames []Frame, n int) []Frame {
+	if n <= 0 || len(frames) == 0 {
+		return nil
+	}
+	if n >= len(frames) {
+		return append([]Frame(nil), frames...)
+	}
+	if n == 1 {
+		return []Frame{frames[0]}
+	}
+	sample := make([]Frame, n)
+	for i := range sample {
+		sample[i] = frames[i*(len(frames)-1)/(n-1)]
+	}
+	return sample
+}
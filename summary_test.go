@@ -0,0 +1,60 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func wrapDeep(depth int, err error) error {
+	if depth <= 1 {
+		return tracerr.Wrap(fmt.Errorf("wrapped: %w", err), "")
+	}
+	return wrapDeep(depth-1, err)
+}
+
+func TestSummaryFrames(t *testing.T) {
+	origin := tracerr.New("some error")
+	err := wrapDeep(30, origin)
+	frames := tracerr.StackTrace(err)
+	// wrapDeep x30, TestSummaryFrames, testing.tRunner, runtime.goexit.
+	if len(frames) != 33 {
+		t.Fatalf("len(frames) = %#v; want %#v", len(frames), 33)
+	}
+
+	summary := tracerr.SummaryFrames(err, 5)
+	expected := []tracerr.Frame{
+		origin.StackTrace()[0],
+		frames[0],
+		frames[10],
+		frames[20],
+		frames[31],
+	}
+	if len(summary) != len(expected) {
+		t.Fatalf("summary = %#v; want %#v", summary, expected)
+	}
+	for i := range expected {
+		if summary[i] != expected[i] {
+			t.Errorf("summary[%d] = %#v; want %#v", i, summary[i], expected[i])
+		}
+	}
+	if summary[0].Func != "github.com/ztrue/tracerr_test.TestSummaryFrames" ||
+		summary[4].Func != "testing.tRunner" {
+		t.Errorf("summary = %#v; want origin TestSummaryFrames and bottom testing.tRunner", summary)
+	}
+
+	if summary := tracerr.SummaryFrames(err, 1); len(summary) != 1 || summary[0] != expected[0] {
+		t.Errorf("tracerr.SummaryFrames(1) = %#v; want origin only", summary)
+	}
+	if summary := tracerr.SummaryFrames(origin, 3); len(summary) != 2 || summary[0] != origin.StackTrace()[0] {
+		t.Errorf("tracerr.SummaryFrames(origin) = %#v; want all user frames", summary)
+	}
+	if summary := tracerr.SummaryFrames(err, 0); summary != nil {
+		t.Errorf("tracerr.SummaryFrames(0) = %#v; want nil", summary)
+	}
+	if summary := tracerr.SummaryFrames(errors.New("some error"), 3); summary != nil {
+		t.Errorf("tracerr.SummaryFrames(regular) = %#v; want nil", summary)
+	}
+}
@@ -0,0 +1,32 @@
+package tracerr
+
+// SymbolizeBudget limits number of program counters resolved to frames
+// on the first call of StackTrace of an error with CompactFrames
+// (and so by Error and print functions),
+// which bounds time of rendering a pathologically deep stack.
+// Frames over the limit are not resolved, they contain program counter only
+// and are displayed as "PC 0x...", see ResolveFrames.
+// Zero means no limit.
+//
+// Unresolved frames are kept regardless of CaptureOwnModuleOnly, CaptureUntil
+// and other settings, since their functions are unknown,
+// so stack trace may go below a boundary which is over the limit.
+var SymbolizeBudget = 0
+
+// ResolveFrames returns stack trace of err the same way as StackTrace,
+// but frames not resolved because of SymbolizeBudget are resolved
+// and filtered by the same settings as the other frames.
+// Frames are resolved once and cached in err.
+// It will be empty if err is not of type Error.
+func ResolveFrames(err error) []Frame {
+	e, ok := err.(*errorData)
+	if !ok || e.lazy == nil {
+		return StackTrace(err)
+	}
+	return e.lazy.resolveAll()
+}
+
+// unresolved checks if frame contains program counter only.
+func (f Frame) unresolved() bool {
+	return f.PC != 0 && f.Func == "" && f.Path == ""
+}
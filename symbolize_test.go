@@ -0,0 +1,119 @@
+package tracerr_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestSymbolizeBudget(t *testing.T) {
+	defer func() {
+		tracerr.CompactFrames = false
+		tracerr.SymbolizeBudget = 0
+	}()
+	tracerr.CompactFrames = true
+	var errs []error
+	for i := 0; i < 2; i++ {
+		errs = append(errs, addFrames(40, "test error"))
+	}
+	// Frames are resolved on the first call of StackTrace.
+	expected := tracerr.StackTrace(errs[0])
+	tracerr.SymbolizeBudget = 5
+	err := errs[1]
+	frames := tracerr.StackTrace(err)
+	if len(frames) != len(expected) {
+		t.Fatalf("len(frames) = %#v; want %#v", len(frames), len(expected))
+	}
+	for i, frame := range frames {
+		resolved := frame.Func != "" && frame.PC == 0
+		if resolved != (i < tracerr.SymbolizeBudget) {
+			t.Errorf("frames[%#v] = %#v; want resolved to be %t", i, frame, i < tracerr.SymbolizeBudget)
+		}
+	}
+	if s := frames[5].String(); !strings.HasPrefix(s, "PC 0x") {
+		t.Errorf("frames[5].String() = %#v; want PC", s)
+	}
+	if s := err.Error(); !strings.Contains(s, "\n\tPC 0x") {
+		t.Errorf("err.Error() = %#v; want unresolved frames", s)
+	}
+	tracerr.SymbolizeBudget = 0
+	if again := tracerr.StackTrace(err); !reflect.DeepEqual(again, frames) {
+		t.Errorf("tracerr.StackTrace() = %#v; want cached %#v", again, frames)
+	}
+	if resolved := tracerr.ResolveFrames(err); !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("tracerr.ResolveFrames() = %#v; want %#v", resolved, expected)
+	}
+	if resolved := tracerr.ResolveFrames(errs[0]); !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("tracerr.ResolveFrames() = %#v; want %#v", resolved, expected)
+	}
+}
+
+func TestSymbolizeBudgetOwnModuleOnly(t *testing.T) {
+	defer func() {
+		tracerr.CompactFrames = false
+		tracerr.CaptureOwnModuleOnly = false
+		tracerr.SymbolizeBudget = 0
+	}()
+	full := tracerr.StackTrace(addFrames(40, "test error"))
+	tracerr.CaptureOwnModuleOnly = true
+	var errs []error
+	for _, compact := range []bool{false, true} {
+		tracerr.CompactFrames = compact
+		errs = append(errs, addFrames(40, "test error"))
+	}
+	own, err := tracerr.StackTrace(errs[0]), errs[1]
+	if len(own) >= len(full) {
+		t.Fatalf("len(own) = %#v; want less than %#v", len(own), len(full))
+	}
+
+	tracerr.SymbolizeBudget = 5
+	// Unresolved frames are unknown, so they are not cut.
+	frames := tracerr.StackTrace(err)
+	if len(frames) != len(full) {
+		t.Errorf("len(frames) = %#v; want %#v", len(frames), len(full))
+	}
+	if !strings.HasPrefix(frames[len(frames)-1].String(), "PC 0x") {
+		t.Errorf("frames[%#v] = %#v; want unresolved", len(frames)-1, frames[len(frames)-1])
+	}
+	if resolved := tracerr.ResolveFrames(err); !reflect.DeepEqual(resolved, own) {
+		t.Errorf("tracerr.ResolveFrames() = %#v; want %#v", resolved, own)
+	}
+}
+
+func TestSymbolizeBudgetCaptureUntil(t *testing.T) {
+	defer func() {
+		tracerr.CompactFrames = false
+		tracerr.CaptureUntil("")
+		tracerr.SymbolizeBudget = 0
+	}()
+	tracerr.CaptureUntil("boundaryServeHTTP")
+	expected := boundaryServeHTTP().StackTrace()
+	if len(expected) != 2 {
+		t.Fatalf("len(expected) = %#v; want 2", len(expected))
+	}
+	tracerr.CompactFrames = true
+
+	// Boundary within budget is found.
+	tracerr.SymbolizeBudget = 2
+	if frames := boundaryServeHTTP().StackTrace(); !reflect.DeepEqual(frames, expected) {
+		t.Errorf("frames = %#v; want %#v", frames, expected)
+	}
+
+	// Boundary over budget is unknown until frames are resolved.
+	tracerr.SymbolizeBudget = 1
+	err := boundaryServeHTTP()
+	frames := err.StackTrace()
+	if len(frames) <= len(expected) || frames[0] != expected[0] {
+		t.Errorf("frames = %#v; want the first frame and unresolved frames", frames)
+	}
+	for i, frame := range frames[1:] {
+		if !strings.HasPrefix(frame.String(), "PC 0x") {
+			t.Errorf("frames[%#v] = %#v; want unresolved", i+1, frame)
+		}
+	}
+	if resolved := tracerr.ResolveFrames(err); !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("tracerr.ResolveFrames() = %#v; want %#v", resolved, expected)
+	}
+}
@@ -0,0 +1,119 @@
+package tracerr
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultTerminalWidth is a width of terminal used by PrintTerminal
+// if it can not be detected by COLUMNS environment variable.
+// Many shells do not export COLUMNS to programs,
+// so 80 columns are used unless COLUMNS is exported explicitly.
+var DefaultTerminalWidth = 80
+
+// wrapIndent is a hanging indentation of wrapped frame lines.
+const wrapIndent = "    "
+
+// PrintTerminal prints error message with stack trace,
+// where long frame lines are wrapped to the width of terminal,
+// which is taken from COLUMNS or DefaultTerminalWidth.
+// Lines are not wrapped if stdout is not a terminal.
+func PrintTerminal(err error) {
+	width := 0
+	if isTerminal(os.Stdout) {
+		width = terminalWidth()
+	}
+	fmt.Print(SprintWidth(err, width) + LineSeparator)
+}
+
+// SprintWidth returns error output by the same rules as Print,
+// where frame lines longer than width are wrapped with hanging indentation.
+// Width is a number of characters (runes) rather than bytes.
+// Lines are not wrapped if width is not positive.
+func SprintWidth(err error, width int) string {
+	if width <= 0 {
+		return Sprint(err)
+	}
+	return SprintWith(err, func(frame Frame) string {
+		format := FrameFormat
+		if format == nil {
+			format = Frame.String
+		}
+		return strings.Join(wrapLine(format(frame), width), LineSeparator)
+	})
+}
+
+// isTerminal checks if f is a character device, such as a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalWidth returns width of terminal from COLUMNS environment variable
+// or DefaultTerminalWidth.
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return DefaultTerminalWidth
+}
+
+// wrapLine splits line to lines no longer than width runes,
+// breaking it after spaces and slashes where possible.
+// All lines except the first one are indented.
+func wrapLine(line string, width int) []string {
+	if utf8.RuneCountInString(line) <= width {
+		return []string{line}
+	}
+	var lines []string
+	current := ""
+	for _, token := range wrapTokens(line) {
+		tokenLen := utf8.RuneCountInString(token)
+		// Token which does not fit a whole line is split right away.
+		fits := len(wrapIndent)+tokenLen <= width
+		if fits && current != "" && current != wrapIndent && utf8.RuneCountInString(current)+tokenLen > width {
+			lines = append(lines, strings.TrimRight(current, " "))
+			current = wrapIndent
+		}
+		current += token
+		for utf8.RuneCountInString(current) > width && width > len(wrapIndent) {
+			cut := runeOffset(current, width)
+			lines = append(lines, current[:cut])
+			current = wrapIndent + current[cut:]
+		}
+	}
+	return append(lines, strings.TrimRight(current, " "))
+}
+
+// runeOffset returns byte offset of n-th rune of s.
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
+
+// wrapTokens splits line after spaces and slashes.
+func wrapTokens(line string) []string {
+	var tokens []string
+	start := 0
+	for i := 0; i < len(line); i++ {
+		if line[i] == ' ' || line[i] == '/' {
+			tokens = append(tokens, line[start:i+1])
+			start = i + 1
+		}
+	}
+	if start < len(line) {
+		tokens = append(tokens, line[start:])
+	}
+	return tokens
+}
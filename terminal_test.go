@@ -0,0 +1,49 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestSprintWidth(t *testing.T) {
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "github.com/john/doe/pkg.(*Server).handleRequest", Line: 42, Path: "/home/john/go/src/github.com/john/doe/pkg/server.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+	})
+	expected := "some error\n" +
+		"/home/john/go/src/github.com/john/doe/\n" +
+		"    pkg/server.go:42 github.com/john/\n" +
+		"    doe/pkg.(*Server).handleRequest()\n" +
+		"/src/main.go:7 main.main()"
+	if output := tracerr.SprintWidth(err, 40); output != expected {
+		t.Errorf("tracerr.SprintWidth(40) = %#v; want %#v", output, expected)
+	}
+	if output := tracerr.SprintWidth(err, 0); output != tracerr.Sprint(err) {
+		t.Errorf("tracerr.SprintWidth(0) = %#v; want %#v", output, tracerr.Sprint(err))
+	}
+
+	long := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "main.main", Line: 7, Path: "/averyveryverylongdirectoryname/main.go"},
+	})
+	expected = "some error\n" +
+		"/averyveryverylongdi\n" +
+		"    rectoryname/\n" +
+		"    main.go:7\n" +
+		"    main.main()"
+	if output := tracerr.SprintWidth(long, 20); output != expected {
+		t.Errorf("tracerr.SprintWidth(20) = %#v; want %#v", output, expected)
+	}
+
+	utf := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "main.main", Line: 7, Path: "/оченьоченьдлинныйкаталог/main.go"},
+	})
+	expected = "some error\n" +
+		"/оченьоченьдлинныйка\n" +
+		"    талог/main.go:7\n" +
+		"    main.main()"
+	if output := tracerr.SprintWidth(utf, 20); output != expected {
+		t.Errorf("tracerr.SprintWidth(20) = %#v; want %#v", output, expected)
+	}
+}
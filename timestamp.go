@@ -0,0 +1,29 @@
+package tracerr
+
+import (
+	"time"
+)
+
+// CaptureTime makes errors with captured stack trace store time
+// when they are created, see TimeOf.
+var CaptureTime = false
+
+// now returns current time, it is replaced in tests.
+var now = time.Now
+
+// TimeOf returns time when the outermost error in the chain of err
+// is created, which is stored if CaptureTime is enabled.
+// It will be zero time and false if there is no time.
+func TimeOf(err error) (time.Time, bool) {
+	var t time.Time
+	found := false
+	walk(err, func(current error) bool {
+		e, ok := current.(*errorData)
+		if ok && !e.time.IsZero() {
+			t, found = e.time, true
+			return false
+		}
+		return true
+	})
+	return t, found
+}
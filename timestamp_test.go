@@ -0,0 +1,44 @@
+package tracerr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestTimeOf(t *testing.T) {
+	created := time.Date(2020, time.May, 4, 12, 30, 0, 0, time.UTC)
+	defer tracerr.SetNow(func() time.Time {
+		return created
+	})()
+
+	if at, ok := tracerr.TimeOf(tracerr.New("some error")); ok || !at.IsZero() {
+		t.Errorf("tracerr.TimeOf() = %s, %t; want zero time, false", at, ok)
+	}
+
+	tracerr.CaptureTime = true
+	defer func() {
+		tracerr.CaptureTime = false
+	}()
+	err := tracerr.New("some error")
+	cases := []error{
+		err,
+		tracerr.Wrap(err, "failed"),
+		fmt.Errorf("failed: %w", err),
+		tracerr.Wrap(errors.New("regular error"), ""),
+	}
+	for i, c := range cases {
+		if at, ok := tracerr.TimeOf(c); !ok || !at.Equal(created) {
+			t.Errorf("cases[%#v]: tracerr.TimeOf() = %s, %t; want %s, true", i, at, ok, created)
+		}
+	}
+	if at, ok := tracerr.TimeOf(tracerr.CustomError(errors.New("some error"), nil)); ok || !at.IsZero() {
+		t.Errorf("tracerr.TimeOf(CustomError) = %s, %t; want zero time, false", at, ok)
+	}
+	if _, ok := tracerr.TimeOf(nil); ok {
+		t.Errorf("tracerr.TimeOf(nil) is ok; want false")
+	}
+}
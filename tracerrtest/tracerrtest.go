@@ -0,0 +1,27 @@
+// Package tracerrtest provides helpers for testing errors with stack trace.
+//
+// It is a separate package to keep tracerr free of testing dependency.
+package tracerrtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+// ExpectOrigin fails the test if the top frame of err
+// has function name not ending with funcSuffix.
+//
+// The top frame is determined by tracerr.TopFrame.
+func ExpectOrigin(t testing.TB, err error, funcSuffix string) {
+	t.Helper()
+	frame, ok := tracerr.TopFrame(err)
+	if !ok {
+		t.Errorf("tracerrtest: error %#v has no stack trace; want origin %#v", err, funcSuffix)
+		return
+	}
+	if !strings.HasSuffix(frame.Func, funcSuffix) {
+		t.Errorf("tracerrtest: error origin = %#v; want suffix %#v", frame.Func, funcSuffix)
+	}
+}
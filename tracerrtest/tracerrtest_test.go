@@ -0,0 +1,72 @@
+package tracerrtest_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+	"github.com/ztrue/tracerr/tracerrtest"
+)
+
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+type ExpectOriginTestCase struct {
+	Error      error
+	FuncSuffix string
+	Fail       bool
+}
+
+func TestExpectOrigin(t *testing.T) {
+	cases := []ExpectOriginTestCase{
+		{
+			Error:      newError(),
+			FuncSuffix: "tracerrtest_test.newError",
+			Fail:       false,
+		},
+		{
+			Error:      newError(),
+			FuncSuffix: ".newError",
+			Fail:       false,
+		},
+		{
+			Error:      newError(),
+			FuncSuffix: ".TestExpectOrigin",
+			Fail:       true,
+		},
+		{
+			Error:      errors.New("regular error"),
+			FuncSuffix: ".newError",
+			Fail:       true,
+		},
+		{
+			Error:      nil,
+			FuncSuffix: ".newError",
+			Fail:       true,
+		},
+	}
+
+	for i, c := range cases {
+		r := &recorder{TB: t}
+		tracerrtest.ExpectOrigin(r, c.Error, c.FuncSuffix)
+		if failed := len(r.failures) > 0; failed != c.Fail {
+			t.Errorf(
+				"cases[%#v]: failed = %#v; want %#v (failures: %#v)",
+				i, failed, c.Fail, r.failures,
+			)
+		}
+	}
+}
+
+func newError() error {
+	return tracerr.New("some error")
+}
@@ -0,0 +1,47 @@
+package tracerr
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// MaxRenderBytes limits length of error output in bytes,
+// such as returned by Error() method or print functions.
+// Output over the limit is truncated with "...(truncated N bytes)" suffix.
+// Zero means no limit.
+// SprintBoxed limits text inside the box only, SprintMarkdown output is not limited.
+//
+// Error message takes a half of the limit at most,
+// so a part of stack trace is shown even for a huge message.
+var MaxRenderBytes = 0
+
+// truncate limits output to MaxRenderBytes,
+// where first headLen bytes are error message followed by stack trace.
+func truncate(output string, headLen int) string {
+	if MaxRenderBytes <= 0 || len(output) <= MaxRenderBytes {
+		return output
+	}
+	head, body := output[:headLen], output[headLen:]
+	headLimit := len(head)
+	if headLimit > MaxRenderBytes/2 && headLimit+len(body) > MaxRenderBytes {
+		headLimit = MaxRenderBytes / 2
+		if headLimit < MaxRenderBytes-len(body) {
+			headLimit = MaxRenderBytes - len(body)
+		}
+	}
+	return truncateString(head, headLimit) + truncateString(body, MaxRenderBytes-headLimit)
+}
+
+// truncateString cuts s to at most limit bytes on a rune boundary.
+func truncateString(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return fmt.Sprintf("%s...(truncated %d bytes)", s[:limit], len(s)-limit)
+}
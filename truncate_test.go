@@ -0,0 +1,63 @@
+package tracerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestMaxRenderBytes(t *testing.T) {
+	defer func() {
+		tracerr.MaxRenderBytes = 0
+	}()
+	frames := []tracerr.Frame{
+		{Func: "main.foo", Line: 42, Path: "/src/github.com/john/doe/foobar.go"},
+		{Func: "main.bar", Line: 43, Path: "/src/github.com/john/doe/foobar.go"},
+	}
+	err := tracerr.CustomError(errors.New(strings.Repeat("x", 1000)), frames)
+	full := err.Error()
+
+	tracerr.MaxRenderBytes = 2000
+	if err.Error() != full {
+		t.Errorf("err.Error() = %#v; want %#v", err.Error(), full)
+	}
+
+	tracerr.MaxRenderBytes = 100
+	expected := strings.Repeat("x", 50) + "...(truncated 950 bytes)" +
+		"\n\t/src/github.com/john/doe/foobar.go:42 main.foo()" +
+		"...(truncated 50 bytes)"
+	if err.Error() != expected {
+		t.Errorf("err.Error() = %#v; want %#v", err.Error(), expected)
+	}
+
+	expected = strings.Repeat("x", 50) + "...(truncated 950 bytes)" +
+		"\n/src/github.com/john/doe/foobar.go:42 main.foo()\n" +
+		"...(truncated 48 bytes)"
+	if tracerr.Sprint(err) != expected {
+		t.Errorf("tracerr.Sprint(err) = %#v; want %#v", tracerr.Sprint(err), expected)
+	}
+
+	short := tracerr.CustomError(errors.New("short"), frames)
+	expected = "short\n\t/src/github.com/john/doe/foobar.go:42 main.foo()" +
+		"\n\t/src/github.com/john/doe/foobar.go:43 main." +
+		"...(truncated 5 bytes)"
+	if short.Error() != expected {
+		t.Errorf("short.Error() = %#v; want %#v", short.Error(), expected)
+	}
+
+	wrapped := tracerr.Wrap(err, strings.Repeat("y", 1000))
+	expected = strings.Repeat("y", 50) + "...(truncated 950 bytes)" +
+		"\n/src/github.com/john/doe/foobar.go:42 main.foo()\n" +
+		"...(truncated 48 bytes)"
+	if tracerr.SprintTrace(wrapped) != expected {
+		t.Errorf("tracerr.SprintTrace(wrapped) = %#v; want %#v", tracerr.SprintTrace(wrapped), expected)
+	}
+
+	regular := errors.New(strings.Repeat("ы", 100))
+	expected = strings.Repeat("ы", 50) + "...(truncated 100 bytes)"
+	if tracerr.Sprint(regular) != expected {
+		t.Errorf("tracerr.Sprint(regular) = %#v; want %#v", tracerr.Sprint(regular), expected)
+	}
+}
@@ -0,0 +1,52 @@
+package tracerr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func boundaryServeHTTP() tracerr.Error {
+	return handleRequest()
+}
+
+func handleRequest() tracerr.Error {
+	return tracerr.New("some error")
+}
+
+func TestCaptureUntil(t *testing.T) {
+	defer tracerr.CaptureUntil("")
+	frames := boundaryServeHTTP().StackTrace()
+	if len(frames) <= 2 {
+		t.Fatalf("len(frames) = %#v; want frames below boundary", len(frames))
+	}
+
+	tracerr.CaptureUntil("ServeHTTP")
+	frames = boundaryServeHTTP().StackTrace()
+	expected := []string{
+		"github.com/ztrue/tracerr_test.handleRequest",
+		"github.com/ztrue/tracerr_test.boundaryServeHTTP",
+	}
+	if len(frames) != len(expected) {
+		t.Fatalf("len(frames) = %#v; want %#v", len(frames), len(expected))
+	}
+	for i, fn := range expected {
+		if frames[i].Func != fn {
+			t.Errorf("frames[%#v].Func = %#v; want %#v", i, frames[i].Func, fn)
+		}
+	}
+
+	// Boundary frame is kept even if it is dropped by filter.
+	ctx := tracerr.ContextWithConfig(context.Background(), &tracerr.Config{
+		Filter: func(frame tracerr.Frame) bool {
+			return frame.Func != "github.com/ztrue/tracerr_test.TestCaptureUntil"
+		},
+		Until: "TestCaptureUntil",
+	})
+	tracerr.CaptureUntil("")
+	frames = tracerr.NewCtx(ctx, "some error").StackTrace()
+	if len(frames) != 1 || frames[0].Func != "github.com/ztrue/tracerr_test.TestCaptureUntil" {
+		t.Errorf("frames = %#v; want boundary frame only", frames)
+	}
+}
@@ -0,0 +1,68 @@
+package tracerr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValidateError checks if e is a correct implementation of Error,
+// which is useful in tests of custom Error implementations:
+//
+//   - Error() does not panic and returns the same text every time;
+//   - StackTrace() does not panic and returns the same frames every time;
+//   - Unwrap() does not panic, returns the same error every time
+//     and never returns e itself or any other error causing a cycle.
+//
+// It returns all found problems joined by errors.Join or nil if there are none.
+func ValidateError(e Error) error {
+	if e == nil {
+		return errors.New("tracerr: error is nil")
+	}
+	var problems []error
+	check := func(method string, fn func() error) {
+		defer func() {
+			if r := recover(); r != nil {
+				problems = append(problems, fmt.Errorf("tracerr: %s panics: %v", method, r))
+			}
+		}()
+		if err := fn(); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	check("Error()", func() error {
+		if e.Error() != e.Error() {
+			return errors.New("tracerr: Error() returns different text")
+		}
+		return nil
+	})
+	check("StackTrace()", func() error {
+		a, b := e.StackTrace(), e.StackTrace()
+		if len(a) != len(b) {
+			return errors.New("tracerr: StackTrace() returns different number of frames")
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return fmt.Errorf("tracerr: StackTrace() returns different frame %d", i)
+			}
+		}
+		return nil
+	})
+	check("Unwrap()", func() error {
+		a, b := e.Unwrap(), e.Unwrap()
+		if keyA, ok := keyOf(a); ok {
+			if keyB, _ := keyOf(b); keyA != keyB {
+				return errors.New("tracerr: Unwrap() returns different errors")
+			}
+			if keyE, ok := keyOf(e); ok && keyA == keyE {
+				return errors.New("tracerr: Unwrap() returns error itself")
+			}
+		} else if (a == nil) != (b == nil) {
+			return errors.New("tracerr: Unwrap() returns different errors")
+		}
+		if HasCycle(e) {
+			return errors.New("tracerr: Unwrap() causes a cycle")
+		}
+		return nil
+	})
+	return errors.Join(problems...)
+}
@@ -0,0 +1,71 @@
+package tracerr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+// brokenError is an incorrect implementation of tracerr.Error.
+type brokenError struct {
+	calls  int
+	frames []tracerr.Frame
+	self   bool
+}
+
+func (e *brokenError) Error() string {
+	e.calls++
+	return strings.Repeat("x", e.calls)
+}
+
+func (e *brokenError) StackTrace() []tracerr.Frame {
+	if e.frames == nil {
+		panic("no frames")
+	}
+	frames := e.frames
+	e.frames = nil
+	return frames
+}
+
+func (e *brokenError) Unwrap() error {
+	if e.self {
+		return e
+	}
+	return nil
+}
+
+func TestValidateError(t *testing.T) {
+	valid := []tracerr.Error{
+		tracerr.New("some error"),
+		tracerr.Wrap(errors.New("some error"), "failed"),
+		tracerr.CustomError(errors.New("some error"), nil),
+	}
+	for i, e := range valid {
+		if err := tracerr.ValidateError(e); err != nil {
+			t.Errorf("valid[%#v]: tracerr.ValidateError() = %#v; want nil", i, err.Error())
+		}
+	}
+
+	broken := &brokenError{
+		frames: []tracerr.Frame{{Func: "main.main", Line: 7, Path: "/src/main.go"}},
+		self:   true,
+	}
+	err := tracerr.ValidateError(broken)
+	if err == nil {
+		t.Fatalf("tracerr.ValidateError() = nil; want problems")
+	}
+	expected := []string{
+		"tracerr: Error() returns different text",
+		"tracerr: StackTrace() panics: no frames",
+		"tracerr: Unwrap() returns error itself",
+	}
+	if err.Error() != strings.Join(expected, "\n") {
+		t.Errorf("tracerr.ValidateError() = %#v; want %#v", err.Error(), strings.Join(expected, "\n"))
+	}
+
+	if err := tracerr.ValidateError(nil); err == nil || err.Error() != "tracerr: error is nil" {
+		t.Errorf("tracerr.ValidateError(nil) = %#v; want error", err)
+	}
+}
@@ -0,0 +1,81 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func TestWrapHere(t *testing.T) {
+	err := addFrameA("some error")
+	wrapped := tracerr.WrapHere(err, "failed")
+	stacks := tracerr.Stacks(wrapped)
+	if len(stacks) != 2 {
+		t.Fatalf("len(tracerr.Stacks()) = %#v; want %#v", len(stacks), 2)
+	}
+	if len(stacks[0]) != len(err.(tracerr.Error).StackTrace()) || stacks[0][0] != err.(tracerr.Error).StackTrace()[0] {
+		t.Errorf("stacks[0] = %#v; want original stack trace", stacks[0])
+	}
+	if stacks[1][0].Func != "github.com/ztrue/tracerr_test.TestWrapHere" || stacks[1][0].Line != 12 {
+		t.Errorf("stacks[1][0] = %#v; want TestWrapHere:12", stacks[1][0])
+	}
+	if len(tracerr.Stacks(err)) != 1 {
+		t.Errorf("tracerr.Stacks(err) = %#v; want original error not modified", tracerr.Stacks(err))
+	}
+	if firstLine(wrapped.Error()) != "failed" {
+		t.Errorf("firstLine(wrapped.Error()) = %#v; want %#v", firstLine(wrapped.Error()), "failed")
+	}
+
+	regular := tracerr.WrapHere(errors.New("regular"), "")
+	if stacks := tracerr.Stacks(regular); len(stacks) != 1 || stacks[0][0].Func != "github.com/ztrue/tracerr_test.TestWrapHere" {
+		t.Errorf("tracerr.Stacks(regular) = %#v; want a single stack trace", stacks)
+	}
+	if tracerr.WrapHere(nil, "") != nil {
+		t.Errorf("tracerr.WrapHere(nil) != nil")
+	}
+}
+
+func TestDeltaStacks(t *testing.T) {
+	defer func() {
+		tracerr.DeltaStacks = false
+	}()
+	err := tracerr.CustomError(errors.New("some error"), []tracerr.Frame{
+		{Func: "main.read", Line: 12, Path: "/src/read.go"},
+		{Func: "main.load", Line: 30, Path: "/src/load.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+		{Func: "runtime.main", Line: 271, Path: "/go/src/runtime/proc.go"},
+	})
+	err = tracerr.WithStacks(err, []tracerr.Frame{
+		{Func: "main.handle", Line: 40, Path: "/src/handle.go"},
+		{Func: "main.load", Line: 33, Path: "/src/load.go"},
+		{Func: "main.main", Line: 7, Path: "/src/main.go"},
+		{Func: "runtime.main", Line: 271, Path: "/go/src/runtime/proc.go"},
+	})
+	stack := "some error\n" +
+		"\t/src/read.go:12 main.read()\n" +
+		"\t/src/load.go:30 main.load()\n" +
+		"\t/src/main.go:7 main.main()\n" +
+		"\t/go/src/runtime/proc.go:271 runtime.main()\n" +
+		"wrapped at:\n"
+	expected := stack +
+		"\t/src/handle.go:40 main.handle()\n" +
+		"\t/src/load.go:33 main.load()\n" +
+		"\t/src/main.go:7 main.main()\n" +
+		"\t/go/src/runtime/proc.go:271 runtime.main()"
+	if err.Error() != expected {
+		t.Errorf("err.Error() = %#v; want %#v", err.Error(), expected)
+	}
+
+	tracerr.DeltaStacks = true
+	expected = stack +
+		"\t/src/handle.go:40 main.handle()\n" +
+		"\t/src/load.go:33 main.load()\n" +
+		"\t... 2 frames in common"
+	if err.Error() != expected {
+		t.Errorf("err.Error() = %#v; want %#v", err.Error(), expected)
+	}
+	if stacks := tracerr.Stacks(err); len(stacks[1]) != 4 {
+		t.Errorf("tracerr.Stacks() = %#v; want raw frames", stacks)
+	}
+}
@@ -0,0 +1,40 @@
+package tracerr
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	wrappers      []string
+	wrappersMutex sync.RWMutex
+)
+
+// RegisterWrapper registers a function, which wraps functions of tracerr,
+// so frames of this function are skipped at the top of stack trace.
+// Unlike custom skip counts, it survives refactoring of wrappers.
+//
+// It can be a full function name, e.g. "github.com/john/doe/errs.Wrap",
+// or a suffix of it after a slash or a dot, e.g. "errs.Wrap" or "Wrap".
+func RegisterWrapper(funcName string) {
+	wrappersMutex.Lock()
+	defer wrappersMutex.Unlock()
+	wrappers = append(wrappers, funcName)
+}
+
+// registeredWrappers returns names registered by RegisterWrapper.
+func registeredWrappers() []string {
+	wrappersMutex.RLock()
+	defer wrappersMutex.RUnlock()
+	return wrappers
+}
+
+// isWrapper checks if function fn is one of wrappers.
+func isWrapper(wrappers []string, fn string) bool {
+	for _, name := range wrappers {
+		if fn == name || strings.HasSuffix(fn, "."+name) || strings.HasSuffix(fn, "/"+name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package tracerr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+func wrapperA(err error) error {
+	return tracerr.Wrap(err, "")
+}
+
+func wrapperB(err error) error {
+	return wrapperA(err)
+}
+
+func TestRegisterWrapper(t *testing.T) {
+	defer tracerr.ResetWrappers()
+
+	frames := tracerr.StackTrace(wrapperB(errors.New("some error")))
+	if frames[0].Func != "github.com/ztrue/tracerr_test.wrapperA" {
+		t.Errorf("frames[0].Func = %#v; want wrapperA", frames[0].Func)
+	}
+
+	tracerr.RegisterWrapper("tracerr_test.wrapperA")
+	tracerr.RegisterWrapper("github.com/ztrue/tracerr_test.wrapperB")
+	frames = tracerr.StackTrace(wrapperB(errors.New("some error")))
+	if frames[0].Func != "github.com/ztrue/tracerr_test.TestRegisterWrapper" {
+		t.Errorf("frames[0].Func = %#v; want TestRegisterWrapper", frames[0].Func)
+	}
+	for _, frame := range frames {
+		if frame.Func == "github.com/ztrue/tracerr_test.wrapperA" ||
+			frame.Func == "github.com/ztrue/tracerr_test.wrapperB" {
+			t.Errorf("frame = %#v; want wrapper skipped", frame)
+		}
+	}
+
+	// Wrappers are skipped at the top of stack trace only.
+	tracerr.RegisterWrapper("TestRegisterWrapper")
+	frames = tracerr.StackTrace(addFrameA("some error"))
+	if len(frames) < 4 || frames[3].Func != "github.com/ztrue/tracerr_test.TestRegisterWrapper" {
+		t.Errorf("frames = %#v; want TestRegisterWrapper kept", frames)
+	}
+}
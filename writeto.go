@@ -0,0 +1,52 @@
+package tracerr
+
+import (
+	"io"
+)
+
+// textWriter is a writer of error output, such as strings.Builder.
+type textWriter interface {
+	io.Writer
+	io.StringWriter
+}
+
+// countingWriter counts bytes written to w and keeps the first error,
+// after which nothing is written.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
+
+func (cw *countingWriter) WriteString(s string) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := io.WriteString(cw.w, s)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
+
+// WriteTo implements io.WriterTo, it writes the same output as Error() to w
+// frame by frame, so a huge stack trace is not built as a string in memory.
+// Output is built as a string anyway if it is limited by MaxRenderBytes.
+func (e *errorData) WriteTo(w io.Writer) (int64, error) {
+	if e.quiet || MaxRenderBytes > 0 {
+		n, err := io.WriteString(w, e.Error())
+		return int64(n), err
+	}
+	cw := &countingWriter{w: w}
+	e.writeRendered(cw, e.header(), e.StackTrace(), 0, true)
+	return cw.n, cw.err
+}
@@ -0,0 +1,68 @@
+package tracerr_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ztrue/tracerr"
+)
+
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n+len(p) > 10 {
+		return 0, errors.New("write failed")
+	}
+	w.n += len(p)
+	return len(p), nil
+}
+
+func TestWriteTo(t *testing.T) {
+	defer func() {
+		tracerr.CollapseSameFile = false
+		tracerr.MaxRenderBytes = 0
+	}()
+	err := tracerr.WrapHere(tracerr.New("some error"), "failed to read")
+	cases := []struct {
+		Err              error
+		CollapseSameFile bool
+		MaxRenderBytes   int
+	}{
+		{Err: tracerr.New("some error")},
+		{Err: err},
+		{Err: err, CollapseSameFile: true},
+		{Err: err, MaxRenderBytes: 50},
+		{Err: tracerr.Quiet(err)},
+	}
+
+	for i, c := range cases {
+		tracerr.CollapseSameFile = c.CollapseSameFile
+		tracerr.MaxRenderBytes = c.MaxRenderBytes
+		expected := c.Err.Error()
+		buf := &bytes.Buffer{}
+		n, writeErr := c.Err.(io.WriterTo).WriteTo(buf)
+		if writeErr != nil {
+			t.Errorf("cases[%#v]: WriteTo() error: %s", i, writeErr)
+		}
+		if buf.String() != expected {
+			t.Errorf("cases[%#v]: WriteTo() output = %#v; want %#v", i, buf.String(), expected)
+		}
+		if n != int64(len(expected)) {
+			t.Errorf("cases[%#v]: WriteTo() = %#v; want %#v", i, n, len(expected))
+		}
+	}
+
+	tracerr.CollapseSameFile = false
+	tracerr.MaxRenderBytes = 0
+	n, writeErr := err.(io.WriterTo).WriteTo(&failingWriter{})
+	if writeErr == nil || writeErr.Error() != "write failed" {
+		t.Errorf("WriteTo() error = %#v; want %#v", writeErr, "write failed")
+	}
+	if n > 10 {
+		t.Errorf("WriteTo() = %#v; want 10 at most", n)
+	}
+}